@@ -0,0 +1,230 @@
+package termdeco
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// TokenKind is a kind of Token.
+type TokenKind int
+
+const (
+	// TextToken is a run of printable characters.
+	TextToken TokenKind = iota
+	// ControlToken is a single C0 or C1 control character or DEL.
+	ControlToken
+	// EscapeToken is an escape sequence other than CSI and control strings
+	// like ESC 7 or ESC ( B.
+	EscapeToken
+	// CSIToken is a control sequence like ESC [ 1 ; 31 m.
+	CSIToken
+	// OSCToken is an operating system command like ESC ] 0 ; title BEL.
+	OSCToken
+	// StringToken is a DCS, SOS, PM or APC control string terminated by ST.
+	StringToken
+)
+
+// Token is a piece of a string split by NextToken or Tokenize.
+type Token struct {
+	Kind TokenKind
+	// Raw is the text of the token as is, including escape characters.
+	Raw string
+	// Incomplete is true if the sequence isn't terminated properly because
+	// the string ends or an unexpected character interrupts it.
+	Incomplete bool
+}
+
+// NextToken returns the first token of s. The rest of s starts at
+// len(tok.Raw).
+func NextToken(s string) Token {
+	if s == "" {
+		return Token{}
+	}
+	if s[0] == keyEscape {
+		return nextEscape(s)
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	if isControl(r) {
+		return Token{Kind: ControlToken, Raw: s[:n]}
+	}
+	i := n
+	for i < len(s) {
+		r, n := utf8.DecodeRuneInString(s[i:])
+		if isControl(r) {
+			break
+		}
+		i += n
+	}
+	return Token{Kind: TextToken, Raw: s[:i]}
+}
+
+// Tokenize splits s into tokens.
+func Tokenize(s string) []Token {
+	var toks []Token
+	for s != "" {
+		t := NextToken(s)
+		toks = append(toks, t)
+		s = s[len(t.Raw):]
+	}
+	return toks
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f || (r >= 0x80 && r <= 0x9f)
+}
+
+func nextEscape(s string) Token {
+	if len(s) == 1 {
+		return Token{Kind: EscapeToken, Raw: s, Incomplete: true}
+	}
+	switch s[1] {
+	case '[':
+		i := 2
+		for i < len(s) && s[i] >= 0x30 && s[i] <= 0x3f {
+			i++
+		}
+		for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+			i++
+		}
+		if i < len(s) && s[i] >= 0x40 && s[i] <= 0x7e {
+			return Token{Kind: CSIToken, Raw: s[:i+1]}
+		}
+		return Token{Kind: CSIToken, Raw: s[:i], Incomplete: true}
+	case ']':
+		return nextString(s, OSCToken, true)
+	case 'P', 'X', '^', '_':
+		return nextString(s, StringToken, false)
+	}
+	i := 1
+	for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+		i++
+	}
+	if i < len(s) && s[i] >= 0x30 && s[i] <= 0x7e {
+		return Token{Kind: EscapeToken, Raw: s[:i+1]}
+	}
+	return Token{Kind: EscapeToken, Raw: s[:i], Incomplete: true}
+}
+
+// nextString reads a control string terminated by ST (ESC \) or, if bel is
+// true, BEL. CAN, SUB and ESC not followed by \ abort the string.
+func nextString(s string, kind TokenKind, bel bool) Token {
+	for i := 2; i < len(s); i++ {
+		switch s[i] {
+		case 0x07:
+			if bel {
+				return Token{Kind: kind, Raw: s[:i+1]}
+			}
+		case 0x18, 0x1a:
+			return Token{Kind: kind, Raw: s[:i], Incomplete: true}
+		case keyEscape:
+			if i+1 < len(s) && s[i+1] == '\\' {
+				return Token{Kind: kind, Raw: s[:i+2]}
+			}
+			if i+1 == len(s) {
+				return Token{Kind: kind, Raw: s, Incomplete: true}
+			}
+			return Token{Kind: kind, Raw: s[:i], Incomplete: true}
+		}
+	}
+	return Token{Kind: kind, Raw: s, Incomplete: true}
+}
+
+// csiBody returns parameter and intermediate bytes of a CSI token.
+func (t Token) csiBody() (params, inter string) {
+	if t.Kind != CSIToken || len(t.Raw) < 2 {
+		return "", ""
+	}
+	body := t.Raw[2:]
+	if !t.Incomplete {
+		body = body[:len(body)-1]
+	}
+	i := 0
+	for i < len(body) && body[i] >= 0x30 && body[i] <= 0x3f {
+		i++
+	}
+	return body[:i], body[i:]
+}
+
+// Final returns the final byte of a CSI or escape sequence token. It returns
+// 0 for other tokens and incomplete sequences.
+func (t Token) Final() byte {
+	if (t.Kind != CSIToken && t.Kind != EscapeToken) || t.Incomplete {
+		return 0
+	}
+	return t.Raw[len(t.Raw)-1]
+}
+
+// Private returns the private parameter marker, one of '<', '=', '>' and '?',
+// of a CSI token like ESC [ ? 25 h. It returns 0 if there isn't.
+func (t Token) Private() byte {
+	p, _ := t.csiBody()
+	if p != "" && p[0] >= '<' && p[0] <= '?' {
+		return p[0]
+	}
+	return 0
+}
+
+// Intermediate returns intermediate bytes of a CSI or escape sequence token.
+func (t Token) Intermediate() string {
+	if t.Kind == EscapeToken {
+		end := len(t.Raw)
+		if !t.Incomplete {
+			end--
+		}
+		if end < 1 {
+			return ""
+		}
+		return t.Raw[1:end]
+	}
+	_, inter := t.csiBody()
+	return inter
+}
+
+// Params returns numeric parameters of a CSI token. Parameters separated by
+// ';' or ':' are returned in order and an omitted parameter is returned as 0.
+func (t Token) Params() []int {
+	p, _ := t.csiBody()
+	if p != "" && p[0] >= '<' && p[0] <= '?' {
+		p = p[1:]
+	}
+	if p == "" {
+		return nil
+	}
+	params := make([]int, 0, strings.Count(p, ";")+1)
+	start := 0
+	for i := 0; i <= len(p); i++ {
+		if i < len(p) && p[i] != ';' && p[i] != ':' {
+			continue
+		}
+		n, err := strconv.Atoi(p[start:i])
+		if err != nil {
+			n = 0
+		}
+		params = append(params, n)
+		start = i + 1
+	}
+	return params
+}
+
+// Payload returns the content of an OSC or control string token without its
+// introducer and terminator.
+func (t Token) Payload() string {
+	if (t.Kind != OSCToken && t.Kind != StringToken) || len(t.Raw) < 2 {
+		return ""
+	}
+	body := t.Raw[2:]
+	if t.Incomplete {
+		return strings.TrimSuffix(body, "\x1b")
+	}
+	if strings.HasSuffix(body, "\x1b\\") {
+		return body[:len(body)-2]
+	}
+	return body[:len(body)-1]
+}
+
+// IsSGR reports whether t is a Select Graphic Rendition sequence like
+// ESC [ 1 ; 31 m which Decorator uses.
+func (t Token) IsSGR() bool {
+	return t.Kind == CSIToken && t.Final() == 'm' && t.Private() == 0 && t.Intermediate() == ""
+}
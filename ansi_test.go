@@ -0,0 +1,56 @@
+package termdeco
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	s := "a\x1b[1;31mb\x1b[0m\n\x1b]8;;http://example.com\x1b\\c\x1b]0;t\x07\x1b(B\x1b[?25l\x1b[3"
+	expected := []Token{
+		{Kind: TextToken, Raw: "a"},
+		{Kind: CSIToken, Raw: "\x1b[1;31m"},
+		{Kind: TextToken, Raw: "b"},
+		{Kind: CSIToken, Raw: "\x1b[0m"},
+		{Kind: ControlToken, Raw: "\n"},
+		{Kind: OSCToken, Raw: "\x1b]8;;http://example.com\x1b\\"},
+		{Kind: TextToken, Raw: "c"},
+		{Kind: OSCToken, Raw: "\x1b]0;t\x07"},
+		{Kind: EscapeToken, Raw: "\x1b(B"},
+		{Kind: CSIToken, Raw: "\x1b[?25l"},
+		{Kind: CSIToken, Raw: "\x1b[3", Incomplete: true},
+	}
+	toks := Tokenize(s)
+	if !reflect.DeepEqual(toks, expected) {
+		t.Fatalf("Tokenize(%q) =\n%#v\nexpected\n%#v", s, toks, expected)
+	}
+
+	if p := toks[1].Params(); !reflect.DeepEqual(p, []int{1, 31}) {
+		t.Errorf("Params = %v, expected [1 31]", p)
+	}
+	if !toks[1].IsSGR() || toks[9].IsSGR() {
+		t.Error("IsSGR returns wrong result")
+	}
+	if toks[9].Private() != '?' || toks[9].Final() != 'l' {
+		t.Errorf("Private = %q, Final = %q", toks[9].Private(), toks[9].Final())
+	}
+	if toks[5].Payload() != "8;;http://example.com" || toks[7].Payload() != "0;t" {
+		t.Errorf("Payload = %q, %q", toks[5].Payload(), toks[7].Payload())
+	}
+	if toks[8].Intermediate() != "(" {
+		t.Errorf("Intermediate = %q, expected %q", toks[8].Intermediate(), "(")
+	}
+}
+
+func TestTokenizeInterrupted(t *testing.T) {
+	toks := Tokenize("\x1b]0;title\x1b[1m\u009bx")
+	expected := []Token{
+		{Kind: OSCToken, Raw: "\x1b]0;title", Incomplete: true},
+		{Kind: CSIToken, Raw: "\x1b[1m"},
+		{Kind: ControlToken, Raw: "\u009b"},
+		{Kind: TextToken, Raw: "x"},
+	}
+	if !reflect.DeepEqual(toks, expected) {
+		t.Fatalf("Tokenize =\n%#v\nexpected\n%#v", toks, expected)
+	}
+}
@@ -0,0 +1,127 @@
+// asciicast is a package for recording and replaying terminal sessions in the
+// asciicast v2 format used by asciinema.
+//
+// A Recorder is an io.Writer so decorated text can be recorded as is with
+// termdeco's printing functions like
+//
+//	rec := asciicast.NewRecorder(f, asciicast.Header{Width: 80, Height: 24})
+//	termdeco.Fprintln(rec, termdeco.Red("failed").Bold())
+//
+// and a recording is replayed by Player with the original timing.
+package asciicast
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Version is the asciicast format version this package reads and writes.
+const Version = 2
+
+// Event types defined by the asciicast v2 format.
+const (
+	EventOutput = "o"
+	EventInput  = "i"
+	EventMarker = "m"
+	EventResize = "r"
+)
+
+// Header is the first line of an asciicast v2 file.
+type Header struct {
+	Version       int               `json:"version"`
+	Width         int               `json:"width"`
+	Height        int               `json:"height"`
+	Timestamp     int64             `json:"timestamp,omitempty"`
+	Duration      float64           `json:"duration,omitempty"`
+	IdleTimeLimit float64           `json:"idle_time_limit,omitempty"`
+	Command       string            `json:"command,omitempty"`
+	Title         string            `json:"title,omitempty"`
+	Env           map[string]string `json:"env,omitempty"`
+	Theme         *HeaderTheme      `json:"theme,omitempty"`
+}
+
+// Event is a timed event of a recording. Time is the number of seconds since
+// the start of the recording.
+type Event struct {
+	Time float64
+	Type string
+	Data string
+}
+
+// MarshalJSON encodes an Event as a [time, type, data] array.
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{e.Time, e.Type, e.Data})
+}
+
+// UnmarshalJSON decodes an Event from a [time, type, data] array.
+func (e *Event) UnmarshalJSON(b []byte) error {
+	var a []json.RawMessage
+	if err := json.Unmarshal(b, &a); err != nil {
+		return err
+	}
+	if len(a) != 3 {
+		return fmt.Errorf("asciicast: event must have 3 elements, got %d", len(a))
+	}
+	if err := json.Unmarshal(a[0], &e.Time); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(a[1], &e.Type); err != nil {
+		return err
+	}
+	return json.Unmarshal(a[2], &e.Data)
+}
+
+// Cast is a whole recording.
+type Cast struct {
+	Header Header
+	Events []Event
+}
+
+// Decode reads an asciicast v2 file from r.
+func Decode(r io.Reader) (*Cast, error) {
+	dec := json.NewDecoder(r)
+	c := &Cast{}
+	if err := dec.Decode(&c.Header); err != nil {
+		return nil, err
+	}
+	if c.Header.Version != Version {
+		return nil, fmt.Errorf("asciicast: unsupported version %d", c.Header.Version)
+	}
+	for {
+		var e Event
+		err := dec.Decode(&e)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.Events = append(c.Events, e)
+	}
+	return c, nil
+}
+
+// Encode writes c to w in the asciicast v2 format.
+func (c *Cast) Encode(w io.Writer) error {
+	h := c.Header
+	h.Version = Version
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(&h); err != nil {
+		return err
+	}
+	for _, e := range c.Events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Duration returns the time of the last event of c.
+func (c *Cast) Duration() float64 {
+	if len(c.Events) == 0 {
+		return 0
+	}
+	return c.Events[len(c.Events)-1].Time
+}
@@ -0,0 +1,109 @@
+package asciicast
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tatsushid/termdeco"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.t }
+
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestRecorder(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1500000000, 0)}
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf, Header{Width: 80, Height: 24, Title: "test"})
+	rec.Now = clock.Now
+
+	if err := rec.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	clock.Advance(500 * time.Millisecond)
+	termdeco.Fprint(rec, termdeco.Red("red"))
+	clock.Advance(time.Second)
+	rec.Write([]byte("\xe3\x81"))
+	clock.Advance(250 * time.Millisecond)
+	rec.Write([]byte("\x82\n"))
+	rec.Marker("done")
+	rec.Resize(100, 30)
+
+	expected := `{"version":2,"width":80,"height":24,"timestamp":1500000000,"title":"test"}
+[0.5,"o","\u001b[31mred\u001b[0m"]
+[1.75,"o","あ\n"]
+[1.75,"m","done"]
+[1.75,"r","100x30"]
+`
+	if buf.String() != expected {
+		t.Errorf("recorded:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestDecodeEncode(t *testing.T) {
+	src := `{"version":2,"width":80,"height":24,"idle_time_limit":2}
+[0.1,"o","hello "]
+[0.2,"i","x"]
+[5,"o","\u001b[1mworld\u001b[0m"]
+`
+	c, err := Decode(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if len(c.Events) != 3 || c.Events[2].Data != "\x1b[1mworld\x1b[0m" {
+		t.Fatalf("unexpected events: %#v", c.Events)
+	}
+	if c.Duration() != 5 {
+		t.Errorf("Duration = %v, expected 5", c.Duration())
+	}
+
+	var buf bytes.Buffer
+	if err := c.Encode(&buf); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if buf.String() != src {
+		t.Errorf("encoded:\n%s\nexpected:\n%s", buf.String(), src)
+	}
+
+	if _, err := Decode(strings.NewReader(`{"version":1}`)); err == nil {
+		t.Error("Decode should fail with version 1")
+	}
+}
+
+func TestPlayer(t *testing.T) {
+	c := &Cast{
+		Header: Header{Version: 2, IdleTimeLimit: 2},
+		Events: []Event{
+			{Time: 1, Type: EventOutput, Data: "a"},
+			{Time: 1.5, Type: EventInput, Data: "x"},
+			{Time: 2, Type: EventOutput, Data: "b"},
+			{Time: 12, Type: EventOutput, Data: "c"},
+		},
+	}
+
+	var sleeps []time.Duration
+	p := &Player{Speed: 2, Sleep: func(d time.Duration) { sleeps = append(sleeps, d) }}
+	var buf bytes.Buffer
+	if err := p.Play(&buf, c); err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	if buf.String() != "abc" {
+		t.Errorf("played %q, expected %q", buf.String(), "abc")
+	}
+	expected := []time.Duration{500 * time.Millisecond, 500 * time.Millisecond, time.Second}
+	if len(sleeps) != len(expected) {
+		t.Fatalf("sleeps = %v, expected %v", sleeps, expected)
+	}
+	for i := range expected {
+		if sleeps[i] != expected[i] {
+			t.Errorf("sleeps = %v, expected %v", sleeps, expected)
+			break
+		}
+	}
+}
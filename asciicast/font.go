@@ -0,0 +1,267 @@
+package asciicast
+
+// A glyph is a bitmap of a character cell, cellWidth x cellHeight pixels.
+// Bit 0 of each row is its leftmost pixel.
+type glyph [cellHeight]uint8
+
+const (
+	cellWidth  = 8
+	cellHeight = 12
+
+	// glyphTop is the row which the 8x8 ASCII glyphs start at.
+	glyphTop      = 2
+	underlineRow  = 11
+	strikeThruRow = 6
+)
+
+// asciiFont is 8x8 bitmaps of printable ASCII characters from U+0020 to U+007E,
+// based on the public domain font8x8 by Daniel Hepper.
+var asciiFont = [95][8]uint8{
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // U+0020 (space)
+	{0x18, 0x3C, 0x3C, 0x18, 0x18, 0x00, 0x18, 0x00}, // U+0021 (!)
+	{0x36, 0x36, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // U+0022 (")
+	{0x36, 0x36, 0x7F, 0x36, 0x7F, 0x36, 0x36, 0x00}, // U+0023 (#)
+	{0x0C, 0x3E, 0x03, 0x1E, 0x30, 0x1F, 0x0C, 0x00}, // U+0024 ($)
+	{0x00, 0x63, 0x33, 0x18, 0x0C, 0x66, 0x63, 0x00}, // U+0025 (%)
+	{0x1C, 0x36, 0x1C, 0x6E, 0x3B, 0x33, 0x6E, 0x00}, // U+0026 (&)
+	{0x06, 0x06, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00}, // U+0027 (')
+	{0x18, 0x0C, 0x06, 0x06, 0x06, 0x0C, 0x18, 0x00}, // U+0028 (()
+	{0x06, 0x0C, 0x18, 0x18, 0x18, 0x0C, 0x06, 0x00}, // U+0029 ())
+	{0x00, 0x66, 0x3C, 0xFF, 0x3C, 0x66, 0x00, 0x00}, // U+002A (*)
+	{0x00, 0x0C, 0x0C, 0x3F, 0x0C, 0x0C, 0x00, 0x00}, // U+002B (+)
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x0C, 0x0C, 0x06}, // U+002C (,)
+	{0x00, 0x00, 0x00, 0x3F, 0x00, 0x00, 0x00, 0x00}, // U+002D (-)
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x0C, 0x0C, 0x00}, // U+002E (.)
+	{0x60, 0x30, 0x18, 0x0C, 0x06, 0x03, 0x01, 0x00}, // U+002F (/)
+	{0x3E, 0x63, 0x73, 0x7B, 0x6F, 0x67, 0x3E, 0x00}, // U+0030 (0)
+	{0x0C, 0x0E, 0x0C, 0x0C, 0x0C, 0x0C, 0x3F, 0x00}, // U+0031 (1)
+	{0x1E, 0x33, 0x30, 0x1C, 0x06, 0x33, 0x3F, 0x00}, // U+0032 (2)
+	{0x1E, 0x33, 0x30, 0x1C, 0x30, 0x33, 0x1E, 0x00}, // U+0033 (3)
+	{0x38, 0x3C, 0x36, 0x33, 0x7F, 0x30, 0x78, 0x00}, // U+0034 (4)
+	{0x3F, 0x03, 0x1F, 0x30, 0x30, 0x33, 0x1E, 0x00}, // U+0035 (5)
+	{0x1C, 0x06, 0x03, 0x1F, 0x33, 0x33, 0x1E, 0x00}, // U+0036 (6)
+	{0x3F, 0x33, 0x30, 0x18, 0x0C, 0x0C, 0x0C, 0x00}, // U+0037 (7)
+	{0x1E, 0x33, 0x33, 0x1E, 0x33, 0x33, 0x1E, 0x00}, // U+0038 (8)
+	{0x1E, 0x33, 0x33, 0x3E, 0x30, 0x18, 0x0E, 0x00}, // U+0039 (9)
+	{0x00, 0x0C, 0x0C, 0x00, 0x00, 0x0C, 0x0C, 0x00}, // U+003A (:)
+	{0x00, 0x0C, 0x0C, 0x00, 0x00, 0x0C, 0x0C, 0x06}, // U+003B (;)
+	{0x18, 0x0C, 0x06, 0x03, 0x06, 0x0C, 0x18, 0x00}, // U+003C (<)
+	{0x00, 0x00, 0x3F, 0x00, 0x00, 0x3F, 0x00, 0x00}, // U+003D (=)
+	{0x06, 0x0C, 0x18, 0x30, 0x18, 0x0C, 0x06, 0x00}, // U+003E (>)
+	{0x1E, 0x33, 0x30, 0x18, 0x0C, 0x00, 0x0C, 0x00}, // U+003F (?)
+	{0x3E, 0x63, 0x7B, 0x7B, 0x7B, 0x03, 0x1E, 0x00}, // U+0040 (@)
+	{0x0C, 0x1E, 0x33, 0x33, 0x3F, 0x33, 0x33, 0x00}, // U+0041 (A)
+	{0x3F, 0x66, 0x66, 0x3E, 0x66, 0x66, 0x3F, 0x00}, // U+0042 (B)
+	{0x3C, 0x66, 0x03, 0x03, 0x03, 0x66, 0x3C, 0x00}, // U+0043 (C)
+	{0x1F, 0x36, 0x66, 0x66, 0x66, 0x36, 0x1F, 0x00}, // U+0044 (D)
+	{0x7F, 0x46, 0x16, 0x1E, 0x16, 0x46, 0x7F, 0x00}, // U+0045 (E)
+	{0x7F, 0x46, 0x16, 0x1E, 0x16, 0x06, 0x0F, 0x00}, // U+0046 (F)
+	{0x3C, 0x66, 0x03, 0x03, 0x73, 0x66, 0x7C, 0x00}, // U+0047 (G)
+	{0x33, 0x33, 0x33, 0x3F, 0x33, 0x33, 0x33, 0x00}, // U+0048 (H)
+	{0x1E, 0x0C, 0x0C, 0x0C, 0x0C, 0x0C, 0x1E, 0x00}, // U+0049 (I)
+	{0x78, 0x30, 0x30, 0x30, 0x33, 0x33, 0x1E, 0x00}, // U+004A (J)
+	{0x67, 0x66, 0x36, 0x1E, 0x36, 0x66, 0x67, 0x00}, // U+004B (K)
+	{0x0F, 0x06, 0x06, 0x06, 0x46, 0x66, 0x7F, 0x00}, // U+004C (L)
+	{0x63, 0x77, 0x7F, 0x7F, 0x6B, 0x63, 0x63, 0x00}, // U+004D (M)
+	{0x63, 0x67, 0x6F, 0x7B, 0x73, 0x63, 0x63, 0x00}, // U+004E (N)
+	{0x1C, 0x36, 0x63, 0x63, 0x63, 0x36, 0x1C, 0x00}, // U+004F (O)
+	{0x3F, 0x66, 0x66, 0x3E, 0x06, 0x06, 0x0F, 0x00}, // U+0050 (P)
+	{0x1E, 0x33, 0x33, 0x33, 0x3B, 0x1E, 0x38, 0x00}, // U+0051 (Q)
+	{0x3F, 0x66, 0x66, 0x3E, 0x36, 0x66, 0x67, 0x00}, // U+0052 (R)
+	{0x1E, 0x33, 0x07, 0x0E, 0x38, 0x33, 0x1E, 0x00}, // U+0053 (S)
+	{0x3F, 0x2D, 0x0C, 0x0C, 0x0C, 0x0C, 0x1E, 0x00}, // U+0054 (T)
+	{0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x3F, 0x00}, // U+0055 (U)
+	{0x33, 0x33, 0x33, 0x33, 0x33, 0x1E, 0x0C, 0x00}, // U+0056 (V)
+	{0x63, 0x63, 0x63, 0x6B, 0x7F, 0x77, 0x63, 0x00}, // U+0057 (W)
+	{0x63, 0x63, 0x36, 0x1C, 0x1C, 0x36, 0x63, 0x00}, // U+0058 (X)
+	{0x33, 0x33, 0x33, 0x1E, 0x0C, 0x0C, 0x1E, 0x00}, // U+0059 (Y)
+	{0x7F, 0x63, 0x31, 0x18, 0x4C, 0x66, 0x7F, 0x00}, // U+005A (Z)
+	{0x1E, 0x06, 0x06, 0x06, 0x06, 0x06, 0x1E, 0x00}, // U+005B ([)
+	{0x03, 0x06, 0x0C, 0x18, 0x30, 0x60, 0x40, 0x00}, // U+005C (\)
+	{0x1E, 0x18, 0x18, 0x18, 0x18, 0x18, 0x1E, 0x00}, // U+005D (])
+	{0x08, 0x1C, 0x36, 0x63, 0x00, 0x00, 0x00, 0x00}, // U+005E (^)
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF}, // U+005F (_)
+	{0x0C, 0x0C, 0x18, 0x00, 0x00, 0x00, 0x00, 0x00}, // U+0060 (`)
+	{0x00, 0x00, 0x1E, 0x30, 0x3E, 0x33, 0x6E, 0x00}, // U+0061 (a)
+	{0x07, 0x06, 0x06, 0x3E, 0x66, 0x66, 0x3B, 0x00}, // U+0062 (b)
+	{0x00, 0x00, 0x1E, 0x33, 0x03, 0x33, 0x1E, 0x00}, // U+0063 (c)
+	{0x38, 0x30, 0x30, 0x3E, 0x33, 0x33, 0x6E, 0x00}, // U+0064 (d)
+	{0x00, 0x00, 0x1E, 0x33, 0x3F, 0x03, 0x1E, 0x00}, // U+0065 (e)
+	{0x1C, 0x36, 0x06, 0x0F, 0x06, 0x06, 0x0F, 0x00}, // U+0066 (f)
+	{0x00, 0x00, 0x6E, 0x33, 0x33, 0x3E, 0x30, 0x1F}, // U+0067 (g)
+	{0x07, 0x06, 0x36, 0x6E, 0x66, 0x66, 0x67, 0x00}, // U+0068 (h)
+	{0x0C, 0x00, 0x0E, 0x0C, 0x0C, 0x0C, 0x1E, 0x00}, // U+0069 (i)
+	{0x30, 0x00, 0x30, 0x30, 0x30, 0x33, 0x33, 0x1E}, // U+006A (j)
+	{0x07, 0x06, 0x66, 0x36, 0x1E, 0x36, 0x67, 0x00}, // U+006B (k)
+	{0x0E, 0x0C, 0x0C, 0x0C, 0x0C, 0x0C, 0x1E, 0x00}, // U+006C (l)
+	{0x00, 0x00, 0x33, 0x7F, 0x7F, 0x6B, 0x63, 0x00}, // U+006D (m)
+	{0x00, 0x00, 0x1F, 0x33, 0x33, 0x33, 0x33, 0x00}, // U+006E (n)
+	{0x00, 0x00, 0x1E, 0x33, 0x33, 0x33, 0x1E, 0x00}, // U+006F (o)
+	{0x00, 0x00, 0x3B, 0x66, 0x66, 0x3E, 0x06, 0x0F}, // U+0070 (p)
+	{0x00, 0x00, 0x6E, 0x33, 0x33, 0x3E, 0x30, 0x78}, // U+0071 (q)
+	{0x00, 0x00, 0x3B, 0x6E, 0x66, 0x06, 0x0F, 0x00}, // U+0072 (r)
+	{0x00, 0x00, 0x3E, 0x03, 0x1E, 0x30, 0x1F, 0x00}, // U+0073 (s)
+	{0x08, 0x0C, 0x3E, 0x0C, 0x0C, 0x2C, 0x18, 0x00}, // U+0074 (t)
+	{0x00, 0x00, 0x33, 0x33, 0x33, 0x33, 0x6E, 0x00}, // U+0075 (u)
+	{0x00, 0x00, 0x33, 0x33, 0x33, 0x1E, 0x0C, 0x00}, // U+0076 (v)
+	{0x00, 0x00, 0x63, 0x6B, 0x7F, 0x7F, 0x36, 0x00}, // U+0077 (w)
+	{0x00, 0x00, 0x63, 0x36, 0x1C, 0x36, 0x63, 0x00}, // U+0078 (x)
+	{0x00, 0x00, 0x33, 0x33, 0x33, 0x3E, 0x30, 0x1F}, // U+0079 (y)
+	{0x00, 0x00, 0x3F, 0x19, 0x0C, 0x26, 0x3F, 0x00}, // U+007A (z)
+	{0x38, 0x0C, 0x0C, 0x07, 0x0C, 0x0C, 0x38, 0x00}, // U+007B ({)
+	{0x18, 0x18, 0x18, 0x00, 0x18, 0x18, 0x18, 0x00}, // U+007C (|)
+	{0x07, 0x0C, 0x0C, 0x38, 0x0C, 0x0C, 0x07, 0x00}, // U+007D (})
+	{0x6E, 0x3B, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // U+007E (~)
+}
+
+// Arms of box drawing characters. Each is 0 for none, 1 for light and 2 for
+// heavy or double lines.
+type boxArms struct {
+	up, down, left, right uint8
+}
+
+var boxDrawing = map[rune]boxArms{
+	'─': {0, 0, 1, 1}, '━': {0, 0, 2, 2}, '│': {1, 1, 0, 0}, '┃': {2, 2, 0, 0},
+	'┌': {0, 1, 0, 1}, '┐': {0, 1, 1, 0}, '└': {1, 0, 0, 1}, '┘': {1, 0, 1, 0},
+	'├': {1, 1, 0, 1}, '┤': {1, 1, 1, 0}, '┬': {0, 1, 1, 1}, '┴': {1, 0, 1, 1},
+	'┼': {1, 1, 1, 1},
+	'┏': {0, 2, 0, 2}, '┓': {0, 2, 2, 0}, '┗': {2, 0, 0, 2}, '┛': {2, 0, 2, 0},
+	'┣': {2, 2, 0, 2}, '┫': {2, 2, 2, 0}, '┳': {0, 2, 2, 2}, '┻': {2, 0, 2, 2},
+	'╋': {2, 2, 2, 2},
+	'═': {0, 0, 2, 2}, '║': {2, 2, 0, 0}, '╔': {0, 2, 0, 2}, '╗': {0, 2, 2, 0},
+	'╚': {2, 0, 0, 2}, '╝': {2, 0, 2, 0}, '╠': {2, 2, 0, 2}, '╣': {2, 2, 2, 0},
+	'╦': {0, 2, 2, 2}, '╩': {2, 0, 2, 2}, '╬': {2, 2, 2, 2},
+	'╭': {0, 1, 0, 1}, '╮': {0, 1, 1, 0}, '╯': {1, 0, 1, 0}, '╰': {1, 0, 0, 1},
+	'╴': {0, 0, 1, 0}, '╵': {1, 0, 0, 0}, '╶': {0, 0, 0, 1}, '╷': {0, 1, 0, 0},
+}
+
+// glyphOf returns the bitmap of r. Printable ASCII characters, box drawing,
+// block elements and braille patterns are supported and the others are drawn
+// as a hollow box.
+func glyphOf(r rune) glyph {
+	var g glyph
+	switch {
+	case r == ' ' || r == 0:
+	case r > ' ' && r <= '~':
+		copy(g[glyphTop:], asciiFont[r-' '][:])
+	case r >= 0x2800 && r <= 0x28ff:
+		g = braille(uint8(r - 0x2800))
+	case r >= 0x2580 && r <= 0x259f:
+		g = block(r)
+	default:
+		if arms, ok := boxDrawing[r]; ok {
+			g = box(arms)
+			break
+		}
+		for y := glyphTop; y < glyphTop+7; y++ {
+			g[y] = 0x41
+		}
+		g[glyphTop] = 0x7f
+		g[glyphTop+6] = 0x7f
+	}
+	return g
+}
+
+func box(a boxArms) glyph {
+	var g glyph
+	const cx, cy = cellWidth/2 - 1, cellHeight/2 - 1
+	vline := func(from, to int, weight uint8) {
+		mask := uint8(1) << cx
+		if weight == 2 {
+			mask |= 1 << (cx + 1)
+		}
+		for y := from; y < to; y++ {
+			g[y] |= mask
+		}
+	}
+	hline := func(from, to int, weight uint8) {
+		var mask uint8
+		for x := from; x < to; x++ {
+			mask |= 1 << uint(x)
+		}
+		g[cy] |= mask
+		if weight == 2 {
+			g[cy+1] |= mask
+		}
+	}
+	if a.up > 0 {
+		vline(0, cy+1+int(a.up)-1, a.up)
+	}
+	if a.down > 0 {
+		vline(cy, cellHeight, a.down)
+	}
+	if a.left > 0 {
+		hline(0, cx+1+int(a.left)-1, a.left)
+	}
+	if a.right > 0 {
+		hline(cx, cellWidth, a.right)
+	}
+	return g
+}
+
+// braille draws braille dots of bits, each dot 2x2 pixels.
+func braille(bits uint8) glyph {
+	var g glyph
+	dots := [8][2]int{{0, 0}, {0, 1}, {0, 2}, {1, 0}, {1, 1}, {1, 2}, {0, 3}, {1, 3}}
+	for i, d := range dots {
+		if bits&(1<<uint(i)) == 0 {
+			continue
+		}
+		mask := uint8(3) << uint(1+d[0]*4)
+		y := 1 + d[1]*3
+		g[y] |= mask
+		g[y+1] |= mask
+	}
+	return g
+}
+
+// block draws block elements from U+2580 to U+259F.
+func block(r rune) glyph {
+	var g glyph
+	fill := func(x0, y0, x1, y1 int) {
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				g[y] |= 1 << uint(x)
+			}
+		}
+	}
+	switch {
+	case r == 0x2580:
+		fill(0, 0, cellWidth, cellHeight/2)
+	case r >= 0x2581 && r <= 0x2588:
+		n := int(r-0x2580) * cellHeight / 8
+		fill(0, cellHeight-n, cellWidth, cellHeight)
+	case r >= 0x2589 && r <= 0x258f:
+		fill(0, 0, cellWidth-int(r-0x2588), cellHeight)
+	case r == 0x2590:
+		fill(cellWidth/2, 0, cellWidth, cellHeight)
+	case r >= 0x2591 && r <= 0x2593:
+		patterns := [3][2]uint8{{0x11, 0x44}, {0x55, 0xaa}, {0xee, 0xbb}}
+		p := patterns[r-0x2591]
+		for y := range g {
+			g[y] = p[y%2]
+		}
+	case r == 0x2594:
+		fill(0, 0, cellWidth, 1)
+	case r == 0x2595:
+		fill(cellWidth-1, 0, cellWidth, cellHeight)
+	default:
+		// quadrants U+2596 to U+259F
+		quads := [10]uint8{4, 8, 1, 13, 9, 7, 11, 2, 6, 14}
+		q := quads[r-0x2596]
+		hw, hh := cellWidth/2, cellHeight/2
+		if q&1 != 0 {
+			fill(0, 0, hw, hh)
+		}
+		if q&2 != 0 {
+			fill(hw, 0, cellWidth, hh)
+		}
+		if q&4 != 0 {
+			fill(0, hh, hw, cellHeight)
+		}
+		if q&8 != 0 {
+			fill(hw, hh, cellWidth, cellHeight)
+		}
+	}
+	return g
+}
@@ -0,0 +1,385 @@
+package asciicast
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tatsushid/termdeco/vt"
+)
+
+// Theme is the colors of a terminal used for rendering a recording.
+type Theme struct {
+	Foreground, Background color.RGBA
+	// Palette is the 16 ANSI colors, black, red, green, yellow, blue,
+	// magenta, cyan, white and their bright variants.
+	Palette [16]color.RGBA
+}
+
+// DefaultTheme is the xterm's default colors.
+var DefaultTheme = &Theme{
+	Foreground: color.RGBA{0xe5, 0xe5, 0xe5, 0xff},
+	Background: color.RGBA{0x00, 0x00, 0x00, 0xff},
+	Palette: [16]color.RGBA{
+		{0x00, 0x00, 0x00, 0xff}, {0xcd, 0x00, 0x00, 0xff}, {0x00, 0xcd, 0x00, 0xff}, {0xcd, 0xcd, 0x00, 0xff},
+		{0x00, 0x00, 0xee, 0xff}, {0xcd, 0x00, 0xcd, 0xff}, {0x00, 0xcd, 0xcd, 0xff}, {0xe5, 0xe5, 0xe5, 0xff},
+		{0x7f, 0x7f, 0x7f, 0xff}, {0xff, 0x00, 0x00, 0xff}, {0x00, 0xff, 0x00, 0xff}, {0xff, 0xff, 0x00, 0xff},
+		{0x5c, 0x5c, 0xff, 0xff}, {0xff, 0x00, 0xff, 0xff}, {0x00, 0xff, 0xff, 0xff}, {0xff, 0xff, 0xff, 0xff},
+	},
+}
+
+// HeaderTheme is the theme entry of an asciicast v2 header. Colors are in
+// "#rrggbb" form and Palette is 8 or 16 colors separated by ':'.
+type HeaderTheme struct {
+	Fg      string `json:"fg"`
+	Bg      string `json:"bg"`
+	Palette string `json:"palette"`
+}
+
+// Theme converts h to a Theme. The bright colors are the same as the normal
+// ones if h has only 8 palette colors.
+func (h *HeaderTheme) Theme() (*Theme, error) {
+	t := &Theme{}
+	var err error
+	if t.Foreground, err = parseHexColor(h.Fg); err != nil {
+		return nil, err
+	}
+	if t.Background, err = parseHexColor(h.Bg); err != nil {
+		return nil, err
+	}
+	colors := strings.Split(h.Palette, ":")
+	if len(colors) != 8 && len(colors) != 16 {
+		return nil, fmt.Errorf("asciicast: palette must have 8 or 16 colors, got %d", len(colors))
+	}
+	for i := range t.Palette {
+		if t.Palette[i], err = parseHexColor(colors[i%len(colors)]); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func parseHexColor(s string) (color.RGBA, error) {
+	if len(s) != 7 || s[0] != '#' {
+		return color.RGBA{}, fmt.Errorf("asciicast: invalid color %q", s)
+	}
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("asciicast: invalid color %q", s)
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}, nil
+}
+
+// GIFOptions is options of EncodeGIF. The zero value is usable.
+type GIFOptions struct {
+	// Theme is colors of the terminal. The recording's theme or
+	// DefaultTheme is used if it is nil.
+	Theme *Theme
+
+	// FontScale is the size of a character cell as a multiple of 8x12
+	// pixels. 2 is used if it is not positive.
+	FontScale int
+
+	// Padding is the width of the margin around the screen in pixels.
+	Padding int
+
+	// Speed and IdleTimeLimit adjust the timing like those of Player.
+	Speed         float64
+	IdleTimeLimit float64
+
+	// Coalesce is the time window in seconds. Output events within it are
+	// merged into a single frame. It can't be less than 0.02, the shortest
+	// frame delay browsers respect.
+	Coalesce float64
+
+	// LastFrameDelay is how long the last frame is shown in seconds. 1 is
+	// used if it is not positive.
+	LastFrameDelay float64
+
+	// ShowCursor draws the cursor as a reversed cell if it is visible.
+	ShowCursor bool
+
+	// LoopCount is the same as gif.GIF's. 0 loops forever.
+	LoopCount int
+}
+
+// EncodeGIF renders the output events of c on a terminal screen of the size
+// in its header and writes them to w as an animated GIF. Resize events are
+// ignored.
+//
+// The GIF palette starts with the theme colors and the other colors used
+// by 256 color and 24-bit color sequences are added until it is filled.
+// After that, the nearest palette color is used instead.
+func EncodeGIF(w io.Writer, c *Cast, opts *GIFOptions) error {
+	if opts == nil {
+		opts = &GIFOptions{}
+	}
+	theme := opts.Theme
+	if theme == nil && c.Header.Theme != nil {
+		t, err := c.Header.Theme.Theme()
+		if err != nil {
+			return err
+		}
+		theme = t
+	}
+	if theme == nil {
+		theme = DefaultTheme
+	}
+	scale := opts.FontScale
+	if scale <= 0 {
+		scale = 2
+	}
+	coalesce := math.Max(opts.Coalesce, 0.02)
+	lastDelay := opts.LastFrameDelay
+	if lastDelay <= 0 {
+		lastDelay = 1
+	}
+	if c.Header.Width <= 0 || c.Header.Height <= 0 {
+		return fmt.Errorf("asciicast: invalid screen size %dx%d", c.Header.Width, c.Header.Height)
+	}
+
+	r := newRenderer(c.Header.Width, c.Header.Height, theme, scale, opts.Padding)
+	r.showCursor = opts.ShowCursor
+	screen := vt.New(c.Header.Width, c.Header.Height)
+	outs := outputs(c, opts.Speed, opts.IdleTimeLimit)
+
+	// The recording starts with a blank screen.
+	prev := r.render(screen)
+	g := &gif.GIF{Image: []*image.Paletted{prev}, LoopCount: opts.LoopCount}
+	times := []float64{0}
+	frameStart := 0.0
+	for i, o := range outs {
+		if i == 0 || o.time-frameStart >= coalesce {
+			frameStart = o.time
+		}
+		screen.Write([]byte(o.data))
+		if i+1 < len(outs) && outs[i+1].time-frameStart < coalesce {
+			continue
+		}
+		img := r.render(screen)
+		rect := diffRect(prev, img)
+		prev = img
+		if rect.Empty() {
+			continue
+		}
+		if len(g.Image) == 1 && frameStart < coalesce {
+			g.Image[0] = img
+			continue
+		}
+		g.Image = append(g.Image, cropPaletted(img, rect))
+		times = append(times, frameStart)
+	}
+
+	total := 0
+	for i := range g.Image {
+		var end float64
+		if i+1 < len(times) {
+			end = times[i+1]
+		} else {
+			end = times[i] + lastDelay
+		}
+		d := int(math.Round(end*100)) - total
+		if d < 2 {
+			d = 2
+		}
+		total += d
+		g.Delay = append(g.Delay, d)
+		g.Disposal = append(g.Disposal, gif.DisposalNone)
+		g.Image[i].Palette = r.palette
+	}
+	g.Config = image.Config{
+		ColorModel: r.palette,
+		Width:      r.bounds.Dx(),
+		Height:     r.bounds.Dy(),
+	}
+	return gif.EncodeAll(w, g)
+}
+
+type renderer struct {
+	cols, rows int
+	theme      *Theme
+	scale      int
+	padding    int
+	bounds     image.Rectangle
+	showCursor bool
+
+	palette color.Palette
+	index   map[color.RGBA]uint8
+	glyphs  map[rune]glyph
+}
+
+func newRenderer(cols, rows int, theme *Theme, scale, padding int) *renderer {
+	r := &renderer{
+		cols:    cols,
+		rows:    rows,
+		theme:   theme,
+		scale:   scale,
+		padding: padding,
+		index:   make(map[color.RGBA]uint8),
+		glyphs:  make(map[rune]glyph),
+	}
+	r.bounds = image.Rect(0, 0, cols*cellWidth*scale+padding*2, rows*cellHeight*scale+padding*2)
+	r.colorIndex(theme.Background)
+	r.colorIndex(theme.Foreground)
+	for _, c := range theme.Palette {
+		r.colorIndex(c)
+	}
+	return r
+}
+
+// colorIndex returns the palette index of c adding it to the palette if
+// there is room.
+func (r *renderer) colorIndex(c color.RGBA) uint8 {
+	if i, ok := r.index[c]; ok {
+		return i
+	}
+	if len(r.palette) < 256 {
+		i := uint8(len(r.palette))
+		r.palette = append(r.palette, c)
+		r.index[c] = i
+		return i
+	}
+	i := uint8(r.palette.Index(c))
+	r.index[c] = i
+	return i
+}
+
+func (r *renderer) glyph(c rune) glyph {
+	g, ok := r.glyphs[c]
+	if !ok {
+		g = glyphOf(c)
+		r.glyphs[c] = g
+	}
+	return g
+}
+
+// rgb resolves a vt.Color to a color of the theme or 256 color palette.
+func (r *renderer) rgb(c vt.Color, def color.RGBA) color.RGBA {
+	if red, green, blue, ok := c.RGB(); ok {
+		return color.RGBA{red, green, blue, 0xff}
+	}
+	i, ok := c.Index()
+	if !ok {
+		return def
+	}
+	switch {
+	case i < 16:
+		return r.theme.Palette[i]
+	case i < 232:
+		levels := [6]uint8{0, 95, 135, 175, 215, 255}
+		i -= 16
+		return color.RGBA{levels[i/36], levels[i/6%6], levels[i%6], 0xff}
+	default:
+		v := 8 + (i-232)*10
+		return color.RGBA{v, v, v, 0xff}
+	}
+}
+
+func (r *renderer) cellColors(s vt.Style) (fg, bg color.RGBA) {
+	fgColor := s.Fg
+	if i, ok := fgColor.Index(); ok && i < 8 && s.Attrs&vt.Bold != 0 {
+		fgColor = vt.IndexedColor(i + 8)
+	}
+	fg = r.rgb(fgColor, r.theme.Foreground)
+	bg = r.rgb(s.Bg, r.theme.Background)
+	if s.Attrs&vt.Reverse != 0 {
+		fg, bg = bg, fg
+	}
+	if s.Attrs&vt.Faint != 0 {
+		fg = color.RGBA{
+			uint8((int(fg.R) + int(bg.R)) / 2),
+			uint8((int(fg.G) + int(bg.G)) / 2),
+			uint8((int(fg.B) + int(bg.B)) / 2),
+			0xff,
+		}
+	}
+	if s.Attrs&vt.Conceal != 0 {
+		fg = bg
+	}
+	return fg, bg
+}
+
+func (r *renderer) render(s *vt.Screen) *image.Paletted {
+	img := image.NewPaletted(r.bounds, nil)
+	bgIndex := r.colorIndex(r.theme.Background)
+	for i := range img.Pix {
+		img.Pix[i] = bgIndex
+	}
+	cx, cy, visible := s.Cursor()
+	for y := 0; y < r.rows; y++ {
+		for x := 0; x < r.cols; x++ {
+			cell := s.Cell(x, y)
+			style := cell.Style
+			if r.showCursor && visible && x == cx && y == cy {
+				style.Attrs ^= vt.Reverse
+			}
+			c, _ := utf8.DecodeRuneInString(cell.Text)
+			r.drawCell(img, x, y, c, style)
+		}
+	}
+	return img
+}
+
+func (r *renderer) drawCell(img *image.Paletted, cx, cy int, c rune, s vt.Style) {
+	fg, bg := r.cellColors(s)
+	fi, bi := r.colorIndex(fg), r.colorIndex(bg)
+	g := r.glyph(c)
+	if s.Attrs&vt.Bold != 0 {
+		for y := range g {
+			g[y] |= g[y] << 1
+		}
+	}
+	if s.Attrs&vt.Underline != 0 {
+		g[underlineRow] = 0xff
+	}
+	if s.Attrs&vt.Strikethrough != 0 {
+		g[strikeThruRow] = 0xff
+	}
+	x0 := r.padding + cx*cellWidth*r.scale
+	y0 := r.padding + cy*cellHeight*r.scale
+	for gy := 0; gy < cellHeight; gy++ {
+		for gx := 0; gx < cellWidth; gx++ {
+			idx := bi
+			if g[gy]&(1<<uint(gx)) != 0 {
+				idx = fi
+			}
+			for sy := 0; sy < r.scale; sy++ {
+				off := img.PixOffset(x0+gx*r.scale, y0+gy*r.scale+sy)
+				for sx := 0; sx < r.scale; sx++ {
+					img.Pix[off+sx] = idx
+				}
+			}
+		}
+	}
+}
+
+// diffRect returns the smallest rectangle containing all the pixels
+// different between a and b which have the same bounds.
+func diffRect(a, b *image.Paletted) image.Rectangle {
+	rect := image.Rectangle{}
+	bounds := a.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		off := a.PixOffset(bounds.Min.X, y)
+		ra, rb := a.Pix[off:off+bounds.Dx()], b.Pix[off:off+bounds.Dx()]
+		for x := range ra {
+			if ra[x] != rb[x] {
+				rect = rect.Union(image.Rect(bounds.Min.X+x, y, bounds.Min.X+x+1, y+1))
+			}
+		}
+	}
+	return rect
+}
+
+func cropPaletted(img *image.Paletted, rect image.Rectangle) *image.Paletted {
+	dst := image.NewPaletted(rect, img.Palette)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		copy(dst.Pix[dst.PixOffset(rect.Min.X, y):], img.Pix[img.PixOffset(rect.Min.X, y):img.PixOffset(rect.Max.X, y)])
+	}
+	return dst
+}
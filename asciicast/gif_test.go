@@ -0,0 +1,85 @@
+package asciicast
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"testing"
+)
+
+func TestEncodeGIF(t *testing.T) {
+	c := &Cast{
+		Header: Header{Version: 2, Width: 4, Height: 2},
+		Events: []Event{
+			{Time: 0.5, Type: EventOutput, Data: "\x1b[31ma"},
+			{Time: 0.51, Type: EventOutput, Data: "b"},
+			{Time: 1.5, Type: EventOutput, Data: "\x1b[38;2;1;2;3m"},
+			{Time: 2, Type: EventOutput, Data: "\x1b[44m c"},
+		},
+	}
+	var buf bytes.Buffer
+	if err := EncodeGIF(&buf, c, &GIFOptions{FontScale: 1, Padding: 2, Coalesce: 0.1}); err != nil {
+		t.Fatalf("EncodeGIF failed: %v", err)
+	}
+
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("DecodeAll failed: %v", err)
+	}
+	if g.Config.Width != 4*cellWidth+4 || g.Config.Height != 2*cellHeight+4 {
+		t.Errorf("size = %dx%d", g.Config.Width, g.Config.Height)
+	}
+	// "ab" is merged into one frame and the SGR only event makes no frame.
+	expected := []int{50, 150, 100}
+	if len(g.Delay) != len(expected) {
+		t.Fatalf("delays = %v, expected %v", g.Delay, expected)
+	}
+	for i := range expected {
+		if g.Delay[i] != expected[i] {
+			t.Errorf("delays = %v, expected %v", g.Delay, expected)
+			break
+		}
+	}
+
+	red := color.RGBA{0xcd, 0x00, 0x00, 0xff}
+	found := false
+	ab := g.Image[1]
+	for y := 2; y < 2+cellHeight; y++ {
+		for x := 2; x < 2+cellWidth; x++ {
+			if ab.At(x, y) == red {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Error("red glyph is not rendered in the second frame")
+	}
+
+	last := g.Image[2]
+	blue := DefaultTheme.Palette[4]
+	if got := last.At(2+2*cellWidth, 2); got != blue {
+		t.Errorf("background of the third cell = %v, expected %v", got, blue)
+	}
+	if last.Bounds().Min.X != 2+2*cellWidth {
+		t.Errorf("third frame bounds = %v, expected only the changed cells", last.Bounds())
+	}
+}
+
+func TestHeaderTheme(t *testing.T) {
+	h := &HeaderTheme{
+		Fg:      "#ffffff",
+		Bg:      "#102030",
+		Palette: "#000000:#110000:#001100:#000011:#111100:#110011:#001111:#111111",
+	}
+	th, err := h.Theme()
+	if err != nil {
+		t.Fatalf("Theme failed: %v", err)
+	}
+	if th.Background != (color.RGBA{0x10, 0x20, 0x30, 0xff}) || th.Palette[9] != (color.RGBA{0x11, 0, 0, 0xff}) {
+		t.Errorf("unexpected theme: %+v", th)
+	}
+	h.Palette = "#000000"
+	if _, err := h.Theme(); err == nil {
+		t.Error("Theme should fail with 1 palette color")
+	}
+}
@@ -0,0 +1,78 @@
+package asciicast
+
+import (
+	"io"
+	"time"
+)
+
+// Player replays output events of a recording.
+type Player struct {
+	// Speed is a playback speed multiplier. 1 is used if it is not
+	// positive.
+	Speed float64
+
+	// IdleTimeLimit caps the pause between two events in seconds of the
+	// recording. The header's idle_time_limit is used if it is 0 and pauses
+	// aren't capped if it is negative.
+	IdleTimeLimit float64
+
+	// Sleep pauses the playback. It is time.Sleep if nil and can be replaced
+	// for testing.
+	Sleep func(time.Duration)
+}
+
+func (p *Player) sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	if p.Sleep != nil {
+		p.Sleep(d)
+		return
+	}
+	time.Sleep(d)
+}
+
+// Play writes output events of c to w with the recorded timing adjusted by
+// Speed and IdleTimeLimit. The other events are skipped.
+func (p *Player) Play(w io.Writer, c *Cast) error {
+	prev := 0.0
+	for _, o := range outputs(c, p.Speed, p.IdleTimeLimit) {
+		p.sleep(time.Duration((o.time - prev) * float64(time.Second)))
+		prev = o.time
+		if _, err := io.WriteString(w, o.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type timedOutput struct {
+	time float64
+	data string
+}
+
+// outputs returns the output events of c with their times adjusted by speed
+// and idle time limit like Player does.
+func outputs(c *Cast, speed, limit float64) []timedOutput {
+	if speed <= 0 {
+		speed = 1
+	}
+	if limit == 0 {
+		limit = c.Header.IdleTimeLimit
+	}
+	var outs []timedOutput
+	prev, t := 0.0, 0.0
+	for _, e := range c.Events {
+		if e.Type != EventOutput {
+			continue
+		}
+		pause := e.Time - prev
+		if limit > 0 && pause > limit {
+			pause = limit
+		}
+		prev = e.Time
+		t += pause / speed
+		outs = append(outs, timedOutput{time: t, data: e.Data})
+	}
+	return outs
+}
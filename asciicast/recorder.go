@@ -0,0 +1,142 @@
+package asciicast
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Recorder is an io.Writer which records everything written to it as output
+// events of an asciicast v2 stream. The header is written to the underlying
+// writer when the recording starts, and every event is written as soon as it
+// is recorded so a recording is kept even if a program exits abnormally.
+//
+// It is safe to use a Recorder from multiple goroutines.
+type Recorder struct {
+	// Now returns the current time. It is time.Now if nil and can be
+	// replaced for testing.
+	Now func() time.Time
+
+	header  Header
+	enc     *json.Encoder
+	mu      sync.Mutex
+	started bool
+	start   time.Time
+	pending []byte
+	err     error
+}
+
+// NewRecorder returns a Recorder writing an asciicast v2 stream with header h
+// to w. Version and Timestamp of h are filled when the recording starts.
+func NewRecorder(w io.Writer, h Header) *Recorder {
+	return &Recorder{header: h, enc: json.NewEncoder(w)}
+}
+
+func (r *Recorder) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
+
+// Start writes the header and starts the recording. The times of events are
+// relative to this point. It is called by the first recorded event if it
+// isn't called explicitly.
+func (r *Recorder) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.startLocked()
+}
+
+func (r *Recorder) startLocked() error {
+	if r.started {
+		return r.err
+	}
+	r.started = true
+	r.start = r.now()
+	r.header.Version = Version
+	if r.header.Timestamp == 0 {
+		r.header.Timestamp = r.start.Unix()
+	}
+	r.err = r.enc.Encode(&r.header)
+	return r.err
+}
+
+func (r *Recorder) record(typ, data string) error {
+	if err := r.startLocked(); err != nil {
+		return err
+	}
+	t := r.now().Sub(r.start).Seconds()
+	r.err = r.enc.Encode(Event{Time: t, Type: typ, Data: data})
+	return r.err
+}
+
+// Write records p as an output event. If p ends in the middle of a UTF-8
+// encoded character, the incomplete bytes are kept and recorded with the
+// next Write or Flush.
+func (r *Recorder) Write(p []byte) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	buf := append(r.pending, p...)
+	end := completeUTF8(buf)
+	r.pending = append([]byte(nil), buf[end:]...)
+	if end == 0 {
+		return len(p), r.startLocked()
+	}
+	if err := r.record(EventOutput, string(buf[:end])); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Input records p as an input event, data typed by a user.
+func (r *Recorder) Input(p []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.record(EventInput, string(p))
+}
+
+// Marker records a marker event with label.
+func (r *Recorder) Marker(label string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.record(EventMarker, label)
+}
+
+// Resize records that the terminal is resized to cols x rows.
+func (r *Recorder) Resize(cols, rows int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.record(EventResize, fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// Flush records bytes kept by Write as an output event even if they are not
+// a complete UTF-8 sequence.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pending) == 0 {
+		return r.err
+	}
+	data := string(r.pending)
+	r.pending = nil
+	return r.record(EventOutput, data)
+}
+
+// completeUTF8 returns the length of the longest prefix of b which doesn't end
+// with an incomplete UTF-8 sequence.
+func completeUTF8(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(b[i]) {
+			continue
+		}
+		if utf8.FullRune(b[i:]) {
+			return len(b)
+		}
+		return i
+	}
+	return len(b)
+}
@@ -0,0 +1,66 @@
+package termdeco
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Clipboard is a selection written by OSC 52.
+type Clipboard string
+
+const (
+	// ClipboardSystem is the clipboard used by copy and paste.
+	ClipboardSystem Clipboard = "c"
+	// ClipboardPrimary is the X11 primary selection pasted by the middle
+	// button.
+	ClipboardPrimary Clipboard = "p"
+)
+
+// ClipboardMaxSize is the maximum length of base64 encoded data written by
+// CopyToClipboard. Terminals ignore too long sequences silently, so larger
+// data is rejected instead. 0 means no limit.
+var ClipboardMaxSize = 100000
+
+// ErrClipboardTooLarge is returned when the encoded data exceeds
+// ClipboardMaxSize.
+var ErrClipboardTooLarge = errors.New("termdeco: data is too large for the clipboard")
+
+// CopyToClipboard writes an OSC 52 sequence to w which makes the terminal put
+// data into target, the system clipboard if it is empty. It works over SSH
+// as the terminal sets its local clipboard, but many terminals need it to be
+// allowed in their settings. Inside tmux or GNU screen, the sequence is
+// passed through to the outer terminal.
+func CopyToClipboard(w io.Writer, data []byte, target Clipboard) error {
+	enc := base64.StdEncoding.EncodeToString(data)
+	if ClipboardMaxSize > 0 && len(enc) > ClipboardMaxSize {
+		return ErrClipboardTooLarge
+	}
+	_, err := io.WriteString(w, passthrough(clipboardSeq(target, enc)))
+	return err
+}
+
+func clipboardSeq(target Clipboard, payload string) string {
+	if target == "" {
+		target = ClipboardSystem
+	}
+	return oscStart + "52;" + string(target) + ";" + payload + bel
+}
+
+// ReadClipboard asks the terminal tty for the content of target and waits
+// its reply up to timeout. Few terminals allow it and others don't reply,
+// which results in ErrNoReply. tty is put into raw mode while waiting.
+func ReadClipboard(tty *os.File, target Clipboard, timeout time.Duration) ([]byte, error) {
+	t, err := request(tty, passthrough(clipboardSeq(target, "?")), timeout, func(t Token) bool {
+		return t.Kind == OSCToken && strings.HasPrefix(t.Raw, oscStart+"52;")
+	})
+	if err != nil {
+		return nil, err
+	}
+	body := oscBody(t)
+	i := strings.LastIndexByte(body, ';')
+	return base64.StdEncoding.DecodeString(body[i+1:])
+}
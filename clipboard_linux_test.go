@@ -0,0 +1,38 @@
+// +build linux
+
+package termdeco
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadClipboard(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("STY", "")
+	t.Setenv("TERM", "xterm")
+	master, slave := openPty(t)
+
+	req := respond(master, "?\a", "typed\x1b]52;c;aGVsbG8=\x1b\\")
+	data, err := ReadClipboard(slave, "", time.Second)
+	if err != nil {
+		t.Fatalf("ReadClipboard failed: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("data = %q, expected %q", data, "hello")
+	}
+	if r := <-req; r != "\x1b]52;c;?\a" {
+		t.Errorf("request = %q", r)
+	}
+}
+
+func TestReadClipboardTimeout(t *testing.T) {
+	_, slave := openPty(t)
+	start := time.Now()
+	if _, err := ReadClipboard(slave, "", 200*time.Millisecond); err != ErrNoReply {
+		t.Errorf("err = %v, expected ErrNoReply", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("ReadClipboard took %v", d)
+	}
+}
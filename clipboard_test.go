@@ -0,0 +1,51 @@
+package termdeco
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCopyToClipboard(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("STY", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	var b bytes.Buffer
+	if err := CopyToClipboard(&b, []byte("token"), ""); err != nil {
+		t.Fatalf("CopyToClipboard failed: %v", err)
+	}
+	if expected := "\x1b]52;c;dG9rZW4=\a"; b.String() != expected {
+		t.Errorf("got %q, expected %q", b.String(), expected)
+	}
+
+	b.Reset()
+	t.Setenv("TMUX", "/tmp/tmux-0/default,1,0")
+	CopyToClipboard(&b, []byte("url"), ClipboardPrimary)
+	if expected := "\x1bPtmux;\x1b\x1b]52;p;dXJs\a\x1b\\"; b.String() != expected {
+		t.Errorf("got %q in tmux, expected %q", b.String(), expected)
+	}
+
+	b.Reset()
+	t.Setenv("TMUX", "")
+	t.Setenv("STY", "1234.pts-0.host")
+	CopyToClipboard(&b, bytes.Repeat([]byte("a"), 60), "")
+	chunks := strings.Split(strings.TrimSuffix(b.String(), "\x1b\\"), "\x1b\\")
+	if len(chunks) != 2 || !strings.HasPrefix(chunks[0], "\x1bP\x1b]52;c;") || len(chunks[0]) != 2+screenChunk {
+		t.Errorf("got %q in screen", b.String())
+	}
+}
+
+func TestCopyToClipboardTooLarge(t *testing.T) {
+	old := ClipboardMaxSize
+	ClipboardMaxSize = 8
+	defer func() { ClipboardMaxSize = old }()
+
+	var b bytes.Buffer
+	if err := CopyToClipboard(&b, []byte("123456789"), ""); err != ErrClipboardTooLarge {
+		t.Errorf("err = %v, expected ErrClipboardTooLarge", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("written %q, expected nothing", b.String())
+	}
+}
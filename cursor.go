@@ -0,0 +1,169 @@
+package termdeco
+
+import (
+	"strconv"
+)
+
+// Escape sequences to control the cursor and the screen. Print them by
+// Print, Fprint and so on, so they are also translated on legacy Windows
+// consoles.
+const (
+	// SaveCursor saves the cursor position and RestoreCursor moves the
+	// cursor back to it.
+	SaveCursor    = "\x1b7"
+	RestoreCursor = "\x1b8"
+	// ShowCursor and HideCursor show and hide the cursor.
+	ShowCursor = "\x1b[?25h"
+	HideCursor = "\x1b[?25l"
+	// EnterAltScreen switches to the alternate screen saving the cursor
+	// and LeaveAltScreen switches back to the main screen.
+	EnterAltScreen = "\x1b[?1049h"
+	LeaveAltScreen = "\x1b[?1049l"
+	// ResetScrollRegion makes the whole screen the scroll region.
+	ResetScrollRegion = "\x1b[r"
+)
+
+func csi(n int, final string) string {
+	if n == 1 {
+		return "\x1b[" + final
+	}
+	return "\x1b[" + strconv.Itoa(n) + final
+}
+
+// CursorUp returns a sequence to move the cursor up n lines. It returns an
+// empty string if n is 0 or less. It's the same for the other movements.
+func CursorUp(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return csi(n, "A")
+}
+
+// CursorDown returns a sequence to move the cursor down n lines.
+func CursorDown(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return csi(n, "B")
+}
+
+// CursorForward returns a sequence to move the cursor right n columns.
+func CursorForward(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return csi(n, "C")
+}
+
+// CursorBack returns a sequence to move the cursor left n columns.
+func CursorBack(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return csi(n, "D")
+}
+
+// CursorColumn returns a sequence to move the cursor to column col of the
+// current line. Columns start at 1.
+func CursorColumn(col int) string {
+	if col < 1 {
+		col = 1
+	}
+	return csi(col, "G")
+}
+
+// CursorPosition returns a sequence to move the cursor to row and col.
+// Rows and columns start at 1 from the top left corner of the screen.
+func CursorPosition(row, col int) string {
+	if row < 1 {
+		row = 1
+	}
+	if col < 1 {
+		col = 1
+	}
+	if row == 1 && col == 1 {
+		return "\x1b[H"
+	}
+	return "\x1b[" + strconv.Itoa(row) + ";" + strconv.Itoa(col) + "H"
+}
+
+// EraseMode is the range to erase by EraseLine and EraseDisplay.
+type EraseMode int
+
+const (
+	// EraseToEnd erases from the cursor to the end.
+	EraseToEnd EraseMode = iota
+	// EraseToStart erases from the start to the cursor.
+	EraseToStart
+	// EraseAll erases the whole line or screen.
+	EraseAll
+)
+
+func erase(m EraseMode, final string) string {
+	if m == EraseToEnd {
+		return "\x1b[" + final
+	}
+	return "\x1b[" + strconv.Itoa(int(m)) + final
+}
+
+// EraseLine returns a sequence to erase the current line in mode m. The
+// cursor isn't moved.
+func EraseLine(m EraseMode) string {
+	return erase(m, "K")
+}
+
+// EraseDisplay returns a sequence to erase the screen in mode m. The cursor
+// isn't moved.
+func EraseDisplay(m EraseMode) string {
+	return erase(m, "J")
+}
+
+// SetScrollRegion returns a sequence to limit scrolling to rows from top to
+// bottom. Rows start at 1. Terminals move the cursor to the top left corner
+// by it.
+func SetScrollRegion(top, bottom int) string {
+	if top < 1 {
+		top = 1
+	}
+	if bottom < top {
+		return ResetScrollRegion
+	}
+	return "\x1b[" + strconv.Itoa(top) + ";" + strconv.Itoa(bottom) + "r"
+}
+
+// ScrollUp returns a sequence to scroll the scroll region up n lines, adding
+// blank lines at the bottom.
+func ScrollUp(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return csi(n, "S")
+}
+
+// ScrollDown returns a sequence to scroll the scroll region down n lines,
+// adding blank lines at the top.
+func ScrollDown(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return csi(n, "T")
+}
+
+// CursorShape is a shape of the cursor set by SetCursorShape.
+type CursorShape int
+
+const (
+	// CursorDefault is the shape configured in the terminal.
+	CursorDefault CursorShape = iota
+	CursorBlinkingBlock
+	CursorSteadyBlock
+	CursorBlinkingUnderline
+	CursorSteadyUnderline
+	CursorBlinkingBar
+	CursorSteadyBar
+)
+
+// SetCursorShape returns a DECSCUSR sequence to change the cursor shape.
+func SetCursorShape(s CursorShape) string {
+	return "\x1b[" + strconv.Itoa(int(s)) + " q"
+}
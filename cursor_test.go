@@ -0,0 +1,35 @@
+package termdeco
+
+import "testing"
+
+func TestCursorSequences(t *testing.T) {
+	tests := []struct {
+		got, expected string
+	}{
+		{CursorUp(1), "\x1b[A"},
+		{CursorUp(3), "\x1b[3A"},
+		{CursorUp(0), ""},
+		{CursorDown(2), "\x1b[2B"},
+		{CursorForward(4), "\x1b[4C"},
+		{CursorBack(-1), ""},
+		{CursorColumn(0), "\x1b[G"},
+		{CursorColumn(5), "\x1b[5G"},
+		{CursorPosition(1, 1), "\x1b[H"},
+		{CursorPosition(3, 7), "\x1b[3;7H"},
+		{CursorPosition(0, 2), "\x1b[1;2H"},
+		{EraseLine(EraseToEnd), "\x1b[K"},
+		{EraseLine(EraseAll), "\x1b[2K"},
+		{EraseDisplay(EraseToStart), "\x1b[1J"},
+		{SetScrollRegion(2, 5), "\x1b[2;5r"},
+		{SetScrollRegion(5, 2), "\x1b[r"},
+		{ScrollUp(1), "\x1b[S"},
+		{ScrollDown(2), "\x1b[2T"},
+		{SetCursorShape(CursorSteadyBar), "\x1b[6 q"},
+		{SetCursorShape(CursorDefault), "\x1b[0 q"},
+	}
+	for i, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("[%d] got %q, expected %q", i, tt.got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+// +build windows
+
+package termdeco
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+type (
+	consoleCursorInfo struct {
+		size    dword
+		visible int32
+	}
+	charInfo struct {
+		char wchar
+		attr word
+	}
+)
+
+var (
+	procSetConsoleCursorPosition    = modkernel32.NewProc("SetConsoleCursorPosition")
+	procGetConsoleCursorInfo        = modkernel32.NewProc("GetConsoleCursorInfo")
+	procSetConsoleCursorInfo        = modkernel32.NewProc("SetConsoleCursorInfo")
+	procFillConsoleOutputCharacterW = modkernel32.NewProc("FillConsoleOutputCharacterW")
+	procFillConsoleOutputAttribute  = modkernel32.NewProc("FillConsoleOutputAttribute")
+	procScrollConsoleScreenBufferW  = modkernel32.NewProc("ScrollConsoleScreenBufferW")
+	procGetConsoleTitleW            = modkernel32.NewProc("GetConsoleTitleW")
+	procSetConsoleTitleW            = modkernel32.NewProc("SetConsoleTitleW")
+)
+
+// savedCursor is the cursor position saved by SaveCursor.
+var savedCursor coord
+
+// titleStack is titles saved by PushTitle.
+var titleStack []string
+
+// packed returns c as a COORD passed by value.
+func (c coord) packed() uintptr {
+	return uintptr(uint16(c.x)) | uintptr(uint16(c.y))<<16
+}
+
+// callConsole calls proc with up to 6 args.
+func callConsole(proc *syscall.LazyProc, args ...uintptr) (err error) {
+	a := make([]uintptr, 6)
+	copy(a, args)
+	var r1 uintptr
+	var e1 syscall.Errno
+	if len(args) <= 3 {
+		r1, _, e1 = syscall.Syscall(proc.Addr(), uintptr(len(args)), a[0], a[1], a[2])
+	} else {
+		r1, _, e1 = syscall.Syscall6(proc.Addr(), uintptr(len(args)), a[0], a[1], a[2], a[3], a[4], a[5])
+	}
+	if r1 == 0 {
+		if e1 != 0 {
+			err = error(e1)
+		} else {
+			err = syscall.EINVAL
+		}
+	}
+	return
+}
+
+// fillConsole erases n cells from pos with attr.
+func fillConsole(h syscall.Handle, pos coord, n int, attr word) error {
+	if n <= 0 {
+		return nil
+	}
+	var written dword
+	err := callConsole(procFillConsoleOutputCharacterW, uintptr(h), uintptr(' '), uintptr(n), pos.packed(), uintptr(unsafe.Pointer(&written)))
+	if err != nil {
+		return err
+	}
+	return callConsole(procFillConsoleOutputAttribute, uintptr(h), uintptr(attr), uintptr(n), pos.packed(), uintptr(unsafe.Pointer(&written)))
+}
+
+// scrollConsole scrolls the window up n lines, or down if n is negative.
+func scrollConsole(h syscall.Handle, info *consoleScreenBufferInfo, n int) error {
+	win := info.window
+	height := int(win.bottom-win.top) + 1
+	if n >= height || -n >= height {
+		return fillConsole(h, coord{0, win.top}, height*int(info.size.x), info.attributes)
+	}
+	src := smallRect{0, win.top, info.size.x - 1, win.bottom}
+	dest := coord{0, win.top}
+	if n > 0 {
+		src.top += short(n)
+	} else {
+		src.bottom += short(n)
+		dest.y -= short(n)
+	}
+	clip := smallRect{0, win.top, info.size.x - 1, win.bottom}
+	fill := charInfo{' ', info.attributes}
+	return callConsole(procScrollConsoleScreenBufferW, uintptr(h), uintptr(unsafe.Pointer(&src)), uintptr(unsafe.Pointer(&clip)), dest.packed(), uintptr(unsafe.Pointer(&fill)))
+}
+
+func setCursorInfo(h syscall.Handle, update func(*consoleCursorInfo)) error {
+	var ci consoleCursorInfo
+	if err := callConsole(procGetConsoleCursorInfo, uintptr(h), uintptr(unsafe.Pointer(&ci))); err != nil {
+		return err
+	}
+	update(&ci)
+	return callConsole(procSetConsoleCursorInfo, uintptr(h), uintptr(unsafe.Pointer(&ci)))
+}
+
+func setConsoleTitle(title string) error {
+	p, err := syscall.UTF16PtrFromString(title)
+	if err != nil {
+		return err
+	}
+	return callConsole(procSetConsoleTitleW, uintptr(unsafe.Pointer(p)))
+}
+
+func getConsoleTitle() (string, error) {
+	buf := make([]uint16, 1024)
+	r1, _, e1 := syscall.Syscall(procGetConsoleTitleW.Addr(), 2, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)), 0)
+	if r1 == 0 && e1 != 0 {
+		return "", error(e1)
+	}
+	return syscall.UTF16ToString(buf), nil
+}
+
+// consoleTitle translates a window title sequence t.
+func consoleTitle(t Token) error {
+	if t.Kind == OSCToken {
+		body := oscBody(t)
+		if strings.HasPrefix(body, "0;") || strings.HasPrefix(body, "2;") {
+			return setConsoleTitle(body[2:])
+		}
+		return nil
+	}
+	switch param(t.Params(), 0, 0) {
+	case 22:
+		title, err := getConsoleTitle()
+		if err != nil {
+			return err
+		}
+		titleStack = append(titleStack, title)
+	case 23:
+		if len(titleStack) == 0 {
+			return nil
+		}
+		title := titleStack[len(titleStack)-1]
+		titleStack = titleStack[:len(titleStack)-1]
+		return setConsoleTitle(title)
+	}
+	return nil
+}
+
+// cursorSize returns the percentage of a cell filled by the cursor of shape
+// s. Legacy consoles have no bar cursor, so it is drawn as a thin underline.
+func cursorSize(s int) dword {
+	switch CursorShape(s) {
+	case CursorBlinkingBlock, CursorSteadyBlock:
+		return 100
+	case CursorBlinkingBar, CursorSteadyBar:
+		return 10
+	}
+	return 25
+}
+
+// consoleControl translates a cursor, screen or window title control
+// sequence t into console API calls. Sequences the console can't handle, like
+// scroll regions and the alternate screen, are ignored.
+func consoleControl(f *os.File, t Token) error {
+	if t.Kind == OSCToken || (t.Kind == CSIToken && t.Final() == 't' && t.Private() == 0) {
+		return consoleTitle(t)
+	}
+	h, err := prepareConsole(f)
+	if err != nil {
+		return err
+	}
+	var info consoleScreenBufferInfo
+	if err := sysGetConsoleScreenBufferInfo(h, &info); err != nil {
+		return err
+	}
+	pos, win := info.cursorPosition, info.window
+
+	if t.Kind == EscapeToken {
+		switch t.Raw {
+		case SaveCursor:
+			savedCursor = pos
+		case RestoreCursor:
+			return setCursorPosition(h, &info, savedCursor)
+		}
+		return nil
+	}
+	if t.Kind != CSIToken {
+		return nil
+	}
+	params := t.Params()
+	switch {
+	case t.Private() == '?':
+		if t.Final() != 'h' && t.Final() != 'l' {
+			return nil
+		}
+		for _, p := range params {
+			if p == 25 {
+				visible := t.Final() == 'h'
+				return setCursorInfo(h, func(ci *consoleCursorInfo) {
+					ci.visible = 0
+					if visible {
+						ci.visible = 1
+					}
+				})
+			}
+		}
+		return nil
+	case t.Private() != 0:
+		return nil
+	case t.Intermediate() == " " && t.Final() == 'q':
+		return setCursorInfo(h, func(ci *consoleCursorInfo) {
+			ci.size = cursorSize(param(params, 0, 0))
+		})
+	case t.Intermediate() != "":
+		return nil
+	}
+
+	n := param(params, 0, 1)
+	switch t.Final() {
+	case 'A':
+		pos.y -= short(n)
+	case 'B':
+		pos.y += short(n)
+	case 'C':
+		pos.x += short(n)
+	case 'D':
+		pos.x -= short(n)
+	case 'E':
+		pos.x, pos.y = 0, pos.y+short(n)
+	case 'F':
+		pos.x, pos.y = 0, pos.y-short(n)
+	case 'G':
+		pos.x = short(n - 1)
+	case 'd':
+		pos.y = win.top + short(n-1)
+	case 'H', 'f':
+		pos.y = win.top + short(n-1)
+		pos.x = short(param(params, 1, 1) - 1)
+	case 's':
+		savedCursor = pos
+		return nil
+	case 'u':
+		return setCursorPosition(h, &info, savedCursor)
+	case 'S':
+		return scrollConsole(h, &info, n)
+	case 'T':
+		return scrollConsole(h, &info, -n)
+	case 'K':
+		width := int(info.size.x)
+		switch param(params, 0, 0) {
+		case 0:
+			return fillConsole(h, pos, width-int(pos.x), info.attributes)
+		case 1:
+			return fillConsole(h, coord{0, pos.y}, int(pos.x)+1, info.attributes)
+		case 2:
+			return fillConsole(h, coord{0, pos.y}, width, info.attributes)
+		}
+		return nil
+	case 'J':
+		width := int(info.size.x)
+		switch param(params, 0, 0) {
+		case 0:
+			return fillConsole(h, pos, int(win.bottom-pos.y)*width+width-int(pos.x), info.attributes)
+		case 1:
+			return fillConsole(h, coord{0, win.top}, int(pos.y-win.top)*width+int(pos.x)+1, info.attributes)
+		case 2:
+			return fillConsole(h, coord{0, win.top}, int(win.bottom-win.top+1)*width, info.attributes)
+		}
+		return nil
+	default:
+		return nil
+	}
+	return setCursorPosition(h, &info, pos)
+}
+
+// setCursorPosition moves the cursor to pos clamped into the window.
+func setCursorPosition(h syscall.Handle, info *consoleScreenBufferInfo, pos coord) error {
+	if pos.x < 0 {
+		pos.x = 0
+	}
+	if pos.x >= info.size.x {
+		pos.x = info.size.x - 1
+	}
+	if pos.y < info.window.top {
+		pos.y = info.window.top
+	}
+	if pos.y > info.window.bottom {
+		pos.y = info.window.bottom
+	}
+	return callConsole(procSetConsoleCursorPosition, uintptr(h), pos.packed())
+}
+
+// param returns the i-th parameter, or def if it is omitted or 0.
+func param(params []int, i, def int) int {
+	if i < len(params) && params[i] > 0 {
+		return params[i]
+	}
+	return def
+}
@@ -0,0 +1,57 @@
+package termdeco_test
+
+import (
+	"testing"
+
+	"github.com/tatsushid/termdeco"
+	"github.com/tatsushid/termdeco/termdecotest"
+)
+
+func TestDecoratorStyles(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{termdeco.Sprint(termdeco.BrightRed("test").BgGreen()), "[fg:bright-red bg:green]test[/]"},
+		{termdeco.Sprint(termdeco.BrightWhite("bold").Bold().Underline().BgBlack()), "[fg:bright-white bg:black bold underline]bold[/]"},
+		{termdeco.Sprintf("Test %d", termdeco.Blue(1234).BgYellow()), "Test [fg:blue bg:yellow]1234[/]"},
+		{termdeco.Sprintf("%-6s|", termdeco.Red("ab").Green()), "[fg:green]ab    [/]|"},
+		{termdeco.Sprint(termdeco.NewDecorator()), "<nil>"},
+	}
+	for _, tt := range tests {
+		if m := termdecotest.Markup(tt.got); m != tt.want {
+			t.Errorf("Markup(%q) = %q, expected %q", tt.got, m, tt.want)
+		}
+	}
+}
+
+func TestDecoratorGolden(t *testing.T) {
+	termdecotest.Golden(t, "decorator", termdeco.Sprintln("Result:", termdeco.Green("PASS").Bold(), termdeco.Underline("3 tests")))
+}
+
+func TestDecoratorPerLine(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{termdeco.Sprint(termdeco.BgBlue("a\nb\n")), "\x1b[44ma\x1b[0m\n\x1b[44mb\x1b[0m\n"},
+		{termdeco.Sprint(termdeco.BgBlue("a\nb").PerLine(false)), "\x1b[44ma\nb\x1b[0m"},
+		{termdeco.Sprint(termdeco.Red("a\n\nb")), "\x1b[31ma\n\nb\x1b[0m"},
+		{termdeco.Sprint(termdeco.Red("a\n\nb").PerLine(true)), "\x1b[31ma\x1b[0m\n\n\x1b[31mb\x1b[0m"},
+		{termdeco.Sprint(termdeco.BgBlue("ab")), "\x1b[44mab\x1b[0m"},
+		{termdeco.Sprint(termdeco.BgBlue(termdeco.Red("a\nb"))), "\x1b[44m\x1b[31ma\x1b[0m\x1b[0m\n\x1b[44m\x1b[31mb\x1b[0m\x1b[0m"},
+		{termdeco.Sprintf("%-3v", termdeco.BgBlue("a\nbc")), "\x1b[44ma  \x1b[0m\n\x1b[44mbc \x1b[0m"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, expected %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestHyperlinkWithoutStyleClean(t *testing.T) {
+	defer func(v bool) { termdeco.HyperlinksEnabled = v }(termdeco.HyperlinksEnabled)
+	for _, enabled := range []bool{true, false} {
+		termdeco.HyperlinksEnabled = enabled
+		termdecotest.AssertClean(t, termdeco.Sprint(termdeco.Hyperlink("x", "http://a")))
+		termdecotest.AssertClean(t, termdeco.Sprint(termdeco.Red("x").Link("http://a")))
+	}
+}
@@ -0,0 +1,128 @@
+package termdeco
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// HyperlinksEnabled is whether links set by Link are printed as OSC 8
+// hyperlinks. If it is false, they are printed as "text (url)". It is
+// detected from the environment at startup. FORCE_HYPERLINK=1 or 0 in the
+// environment overrides the detection. Fprint and the other printing functions
+// writing to a legacy Windows console print links as "text (url)" even if it
+// is true.
+var HyperlinksEnabled = detectHyperlinks(os.Getenv)
+
+// linkClose ends a hyperlink.
+const linkClose = "\x1b]8;;\x1b\\"
+
+// Hyperlink returns a Decorator printing v as a link to url.
+func Hyperlink(v interface{}, url string) *Decorator {
+	return &Decorator{Value: v, link: url}
+}
+
+// Link makes the value a hyperlink to url. Terminals supporting OSC 8 make it
+// clickable.
+func (d *Decorator) Link(url string) *Decorator { d.link = url; return d }
+
+// LinkID sets the id of the link. Terminals highlight cells of the links with
+// the same id and url together on hover, like a link wrapped over lines.
+func (d *Decorator) LinkID(id string) *Decorator { d.linkID = id; return d }
+
+// withLink wraps s, the formatted value whose text is plain, with the link.
+func (d *Decorator) withLink(s, plain string) string {
+	if d.link == "" {
+		return s
+	}
+	if !HyperlinksEnabled {
+		return s + d.linkSuffix(plain)
+	}
+	params := ""
+	if d.linkID != "" {
+		params = "id=" + escapeURI(d.linkID, ":;")
+	}
+	return oscStart + "8;" + params + ";" + escapeURI(d.link, "") + oscEnd + s + linkClose
+}
+
+// linkSuffix returns the text printed after the value whose text is plain in
+// place of the link when hyperlinks are disabled. It's empty if the text is
+// the url itself.
+func (d *Decorator) linkSuffix(plain string) string {
+	if d.link == "" || HyperlinksEnabled || plain == d.link {
+		return ""
+	}
+	return " (" + Sanitize(d.link, d.sanitize) + ")"
+}
+
+// isLinkToken reports whether t is an OSC 8 sequence opening or closing a
+// hyperlink.
+func isLinkToken(t Token) bool {
+	return t.Kind == OSCToken && !t.Incomplete && strings.HasPrefix(t.Raw, oscStart+"8;")
+}
+
+// isLinkClose reports whether the OSC 8 sequence t closes a hyperlink, which
+// is one with an empty URI.
+func isLinkClose(t Token) bool {
+	return linkURI(t) == ""
+}
+
+// linkURI returns the URI of the OSC 8 sequence t. Its parameters can't have
+// a semicolon, which separates them from the URI.
+func linkURI(t Token) string {
+	parts := strings.SplitN(oscBody(t), ";", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[2]
+}
+
+// escapeURI percent-encodes bytes which can't be in an OSC 8 sequence and
+// bytes in reserved.
+func escapeURI(s, reserved string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c >= 0x7f || strings.IndexByte(reserved, c) >= 0 {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// detectHyperlinks reports whether the terminal described by the environment
+// supports OSC 8 hyperlinks.
+func detectHyperlinks(getenv func(string) string) bool {
+	switch getenv("FORCE_HYPERLINK") {
+	case "":
+	case "0", "false":
+		return false
+	default:
+		return true
+	}
+	if getenv("CI") != "" || getenv("TERM") == "dumb" {
+		return false
+	}
+	for _, key := range []string{"WT_SESSION", "KITTY_WINDOW_ID", "KONSOLE_VERSION", "DOMTERM"} {
+		if getenv(key) != "" {
+			return true
+		}
+	}
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper", "Tabby":
+		return true
+	}
+	if v, err := strconv.Atoi(getenv("VTE_VERSION")); err == nil && v >= 5000 {
+		return true
+	}
+	term := getenv("TERM")
+	for _, prefix := range []string{"xterm-kitty", "xterm-ghostty", "alacritty", "foot", "wezterm"} {
+		if strings.HasPrefix(term, prefix) {
+			return true
+		}
+	}
+	return false
+}
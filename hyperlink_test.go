@@ -0,0 +1,101 @@
+package termdeco
+
+import (
+	"reflect"
+	"testing"
+)
+
+func setHyperlinks(t *testing.T, enabled bool) {
+	old := HyperlinksEnabled
+	HyperlinksEnabled = enabled
+	t.Cleanup(func() { HyperlinksEnabled = old })
+}
+
+func TestHyperlink(t *testing.T) {
+	setHyperlinks(t, true)
+	tests := []struct {
+		got, expected string
+	}{
+		{Sprint(Hyperlink("docs", "https://example.com/")), "\x1b]8;;https://example.com/\x1b\\docs\x1b]8;;\x1b\\"},
+		{Sprint(Red("PROJ-1").Link("https://t.example/PROJ-1").LinkID("p1")), "\x1b]8;id=p1;https://t.example/PROJ-1\x1b\\\x1b[31mPROJ-1\x1b[0m\x1b]8;;\x1b\\"},
+		{Sprint(Hyperlink("x", "a b\x1b]é").LinkID("a;b")), "\x1b]8;id=a%3Bb;a b%1B]%C3%A9\x1b\\x\x1b]8;;\x1b\\"},
+		{Bold(nil).Link("file:///tmp/a.go").Apply("a.go:3"), "\x1b]8;;file:///tmp/a.go\x1b\\\x1b[1ma.go:3\x1b[0m\x1b]8;;\x1b\\"},
+		{NewDecorator().Link("u").Apply("t"), "\x1b]8;;u\x1b\\t\x1b]8;;\x1b\\"},
+	}
+	for i, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("[%d] got %q, expected %q", i, tt.got, tt.expected)
+		}
+	}
+}
+
+func TestHyperlinkFallback(t *testing.T) {
+	setHyperlinks(t, false)
+	tests := []struct {
+		got, expected string
+	}{
+		{Sprint(Hyperlink("docs", "https://example.com/")), "docs (https://example.com/)"},
+		{Sprint(Blue("https://example.com/").Link("https://example.com/")), "\x1b[34mhttps://example.com/\x1b[0m"},
+		{Sprint(Hyperlink("x", "u\x1b[2J")), "x (u\\x1b[2J)"},
+		{Bold(nil).Link("u").Apply("t"), "\x1b[1mt\x1b[0m (u)"},
+		{Sprintf("%-15s|", Hyperlink("x", "http://a")), "x (http://a)   |"},
+		{Sprintf("%15s|", Hyperlink("x", "http://a")), "   x (http://a)|"},
+		{Sprintf("%-15s|", Red("x").Link("http://a")), "\x1b[31mx\x1b[0m (http://a)   |"},
+		{Sprintf("%-10s|", Hyperlink("x", "http://a")), "x (http://a)|"},
+	}
+	for i, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("[%d] got %q, expected %q", i, tt.got, tt.expected)
+		}
+	}
+}
+
+func TestDetectHyperlinks(t *testing.T) {
+	tests := []struct {
+		env      map[string]string
+		expected bool
+	}{
+		{map[string]string{}, false},
+		{map[string]string{"TERM": "xterm-256color"}, false},
+		{map[string]string{"TERM": "xterm-kitty"}, true},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, true},
+		{map[string]string{"WT_SESSION": "1"}, true},
+		{map[string]string{"VTE_VERSION": "6003"}, true},
+		{map[string]string{"VTE_VERSION": "4803"}, false},
+		{map[string]string{"WT_SESSION": "1", "CI": "true"}, false},
+		{map[string]string{"FORCE_HYPERLINK": "1", "TERM": "dumb"}, true},
+		{map[string]string{"FORCE_HYPERLINK": "0", "TERM_PROGRAM": "vscode"}, false},
+	}
+	for i, tt := range tests {
+		getenv := func(key string) string { return tt.env[key] }
+		if got := detectHyperlinks(getenv); got != tt.expected {
+			t.Errorf("[%d] detectHyperlinks(%v) = %v, expected %v", i, tt.env, got, tt.expected)
+		}
+	}
+}
+
+func TestHyperlinkCut(t *testing.T) {
+	setHyperlinks(t, true)
+	const open = "\x1b]8;;http://example.com\x1b\\"
+	s := Sprint(Hyperlink("click here", "http://example.com"))
+	if got, expected := Truncate(s, 6, "…"), open+"click"+linkClose+"…"; got != expected {
+		t.Errorf("Truncate = %q, expected %q", got, expected)
+	}
+	if got, expected := Slice(s, 2, 5), open+"ick"+linkClose; got != expected {
+		t.Errorf("Slice = %q, expected %q", got, expected)
+	}
+	if got, expected := Slice(Sprint(Red("ab").Link("http://example.com")), 1, 2), open+"\x1b[31mb\x1b[0m"+linkClose; got != expected {
+		t.Errorf("Slice of styled link = %q, expected %q", got, expected)
+	}
+
+	lines := Split(Sprint(Hyperlink("x\ny", "u")) + "\nz")
+	expected := []string{"\x1b]8;;u\x1b\\x" + linkClose, "\x1b]8;;u\x1b\\y" + linkClose, "z"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Split = %q, expected %q", lines, expected)
+	}
+
+	got := Wrap(Sprint(Hyperlink("aa bb", "u")), 2, nil)
+	if expected := "\x1b]8;;u\x1b\\aa" + linkClose + "\n\x1b]8;;u\x1b\\bb" + linkClose; got != expected {
+		t.Errorf("Wrap = %q, expected %q", got, expected)
+	}
+}
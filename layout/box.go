@@ -0,0 +1,242 @@
+// layout is a package of primitives to lay out decorated text on a terminal
+// like boxes around content and horizontal rules.
+//
+//	b := layout.NewBox()
+//	b.Title = termdeco.Yellow("Warning").Bold()
+//	b.Render(os.Stdout, "disk usage is over 90%")
+//
+// Everything is measured by display width, so wide characters and escape
+// sequences don't break the alignment.
+package layout
+
+import (
+	"io"
+	"strings"
+
+	"github.com/tatsushid/termdeco"
+)
+
+// Align is horizontal alignment.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// BoxBorder is characters of a box border. They are measured by Width, so
+// box drawing characters occupying two columns by termdeco.AmbiguousWidth are
+// also lined up.
+type BoxBorder struct {
+	TopLeft, Top, TopRight          string
+	Left, Right                     string
+	BottomLeft, Bottom, BottomRight string
+}
+
+var (
+	// BoxASCII draws a box with ASCII characters.
+	BoxASCII = BoxBorder{"+", "-", "+", "|", "|", "+", "-", "+"}
+	// BoxLight draws a box with light box drawing characters.
+	BoxLight = BoxBorder{"┌", "─", "┐", "│", "│", "└", "─", "┘"}
+	// BoxHeavy draws a box with heavy box drawing characters.
+	BoxHeavy = BoxBorder{"┏", "━", "┓", "┃", "┃", "┗", "━", "┛"}
+	// BoxDouble draws a box with double box drawing characters.
+	BoxDouble = BoxBorder{"╔", "═", "╗", "║", "║", "╚", "═", "╝"}
+	// BoxRounded draws a box with light box drawing characters and rounded
+	// corners.
+	BoxRounded = BoxBorder{"╭", "─", "╮", "│", "│", "╰", "─", "╯"}
+)
+
+// Spacing is widths of space on each side.
+type Spacing struct {
+	Top, Right, Bottom, Left int
+}
+
+// Box draws a border around content.
+type Box struct {
+	Border      BoxBorder
+	BorderStyle *termdeco.Decorator
+	// Title is printed on the top border if it isn't nil.
+	Title      interface{}
+	TitleAlign Align
+	// Style is applied to the inside of the box including Padding, like a
+	// background color of a panel.
+	Style   *termdeco.Decorator
+	Padding Spacing
+	// Margin is space outside of the border. The rendered box is always
+	// rectangular including the margin.
+	Margin Spacing
+	// Width is the width of the box including the border but not Margin.
+	// Content is wrapped to fit into it. If it is 0, the box fits the
+	// content.
+	Width int
+}
+
+// NewBox returns a Box with BoxRounded and one column of horizontal padding.
+func NewBox() *Box {
+	return &Box{
+		Border:  BoxRounded,
+		Padding: Spacing{Left: 1, Right: 1},
+	}
+}
+
+// String returns the box around content, which is printed by termdeco.Sprint.
+func (b *Box) String(content interface{}) string {
+	return b.render(content, 0)
+}
+
+// Render writes the box around content to w. If Width is 0, content is
+// wrapped to fit into the width of w's terminal.
+func (b *Box) Render(w io.Writer, content interface{}) error {
+	max := 0
+	if b.Width == 0 {
+		if cols, _, err := termdeco.Size(w); err == nil {
+			max = cols - b.Margin.Left - b.Margin.Right
+		}
+	}
+	_, err := termdeco.Fprintln(w, b.render(content, max))
+	return err
+}
+
+func (b *Box) render(content interface{}, max int) string {
+	s := ""
+	if content != nil {
+		s = termdeco.Sprint(content)
+	}
+	title := ""
+	if b.Title != nil {
+		title = termdeco.Sprint(b.Title)
+	}
+	bd := b.Border
+	frame := termdeco.Width(bd.Left) + termdeco.Width(bd.Right) + b.Padding.Left + b.Padding.Right
+	corners := termdeco.Width(bd.TopLeft) + termdeco.Width(bd.TopRight)
+
+	inner := 0
+	switch {
+	case b.Width > 0:
+		inner = b.Width - frame
+	default:
+		for _, l := range termdeco.Split(s) {
+			if w := termdeco.Width(l); w > inner {
+				inner = w
+			}
+		}
+		// the title is put between a glyph and a space on each side
+		if w := corners + 2*glyphWidth(bd.Top) + termdeco.Width(title) + 2 - frame; w > inner {
+			inner = w
+		}
+		// border lines are drawn with whole glyphs, which may occupy
+		// two columns
+		if r := (inner + frame - corners) % glyphWidth(bd.Top); r != 0 {
+			inner += glyphWidth(bd.Top) - r
+		}
+		if max > 0 && inner > max-frame {
+			inner = max - frame
+		}
+	}
+	if inner < 1 {
+		inner = 1
+	}
+
+	var lines []string
+	for _, l := range termdeco.Split(s) {
+		if termdeco.Width(l) > inner {
+			lines = append(lines, termdeco.Split(termdeco.Wrap(l, inner, &termdeco.WrapOptions{BreakWords: true}))...)
+		} else {
+			lines = append(lines, l)
+		}
+	}
+	for i := 0; i < b.Padding.Top; i++ {
+		lines = append([]string{""}, lines...)
+	}
+	for i := 0; i < b.Padding.Bottom; i++ {
+		lines = append(lines, "")
+	}
+
+	width := inner + frame
+	left := strings.Repeat(" ", b.Margin.Left)
+	right := strings.Repeat(" ", b.Margin.Right)
+	blank := strings.Repeat(" ", b.Margin.Left+width+b.Margin.Right)
+	var out []string
+	for i := 0; i < b.Margin.Top; i++ {
+		out = append(out, blank)
+	}
+	out = append(out, left+b.topLine(title, width-corners)+right)
+	padLeft := strings.Repeat(" ", b.Padding.Left)
+	padRight := strings.Repeat(" ", b.Padding.Right)
+	for _, l := range lines {
+		body := apply(b.Style, padLeft+termdeco.PadRight(l, inner)+padRight)
+		out = append(out, left+apply(b.BorderStyle, b.Border.Left)+body+apply(b.BorderStyle, b.Border.Right)+right)
+	}
+	bottom := bd.BottomLeft + repeat(bd.Bottom, width-termdeco.Width(bd.BottomLeft)-termdeco.Width(bd.BottomRight)) + bd.BottomRight
+	out = append(out, left+apply(b.BorderStyle, bottom)+right)
+	for i := 0; i < b.Margin.Bottom; i++ {
+		out = append(out, blank)
+	}
+	return strings.Join(out, "\n")
+}
+
+// topLine returns the top border having title in it between the corners
+// fill columns apart. The title is truncated to leave a border glyph on each
+// side, or none on a narrow box, and dropped if it doesn't fit at all.
+func (b *Box) topLine(title string, fill int) string {
+	gw := glyphWidth(b.Border.Top)
+	room := fill - 2 - 2*gw
+	if room < 1 {
+		room = fill - 2
+	}
+	if title == "" || room < 1 {
+		return apply(b.BorderStyle, b.Border.TopLeft+repeat(b.Border.Top, fill)+b.Border.TopRight)
+	}
+	title = " " + termdeco.Truncate(title, room, "…") + " "
+	rest := fill - termdeco.Width(title)
+	var before int
+	switch b.TitleAlign {
+	case AlignCenter:
+		before = rest / 2
+	case AlignRight:
+		before = rest - gw
+	default:
+		before = gw
+	}
+	if before > rest {
+		before = rest
+	}
+	if before < 0 {
+		before = 0
+	}
+	before -= before % gw
+	if r := (rest - before) % gw; r != 0 {
+		title += strings.Repeat(" ", r)
+		rest -= r
+	}
+	return apply(b.BorderStyle, b.Border.TopLeft+repeat(b.Border.Top, before)) +
+		title +
+		apply(b.BorderStyle, repeat(b.Border.Top, rest-before)+b.Border.TopRight)
+}
+
+// glyphWidth returns the width of glyph, at least 1.
+func glyphWidth(glyph string) int {
+	if w := termdeco.Width(glyph); w > 1 {
+		return w
+	}
+	return 1
+}
+
+// repeat repeats glyph to occupy n columns. If glyph is wider than a column
+// and n isn't a multiple of its width, the remainder is filled with spaces.
+func repeat(glyph string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	w := glyphWidth(glyph)
+	return strings.Repeat(glyph, n/w) + strings.Repeat(" ", n%w)
+}
+
+func apply(d *termdeco.Decorator, s string) string {
+	if d == nil {
+		return s
+	}
+	return d.Apply(s)
+}
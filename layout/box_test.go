@@ -0,0 +1,103 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/tatsushid/termdeco"
+	"github.com/tatsushid/termdeco/termdecotest"
+)
+
+func TestBox(t *testing.T) {
+	b := NewBox()
+	expected := "" +
+		"╭──────────╮\n" +
+		"│ 日本語   │\n" +
+		"│ ok: " + termdeco.Sprint(termdeco.Green("done")) + " │\n" +
+		"╰──────────╯"
+	if s := b.String("日本語\nok: " + termdeco.Sprint(termdeco.Green("done"))); s != expected {
+		t.Errorf("String =\n%s\nexpected\n%s", s, expected)
+	}
+
+	b.Border = BoxASCII
+	b.Title = "Title"
+	b.Padding = Spacing{1, 2, 1, 2}
+	b.Margin = Spacing{Left: 1, Right: 1}
+	expected = "" +
+		" +- Title -+ \n" +
+		" |         | \n" +
+		" |  abc    | \n" +
+		" |         | \n" +
+		" +---------+ "
+	if s := b.String("abc"); s != expected {
+		t.Errorf("String =\n%s\nexpected\n%s", s, expected)
+	}
+}
+
+func TestBoxWidth(t *testing.T) {
+	b := NewBox()
+	b.Width = 12
+	b.Title = termdeco.Bold("a very long title")
+	b.TitleAlign = AlignCenter
+	b.BorderStyle = termdeco.Yellow(nil)
+	s := b.String("wrapped text in a box")
+	termdecotest.AssertClean(t, s)
+	for i, l := range termdeco.Split(s) {
+		if w := termdeco.Width(l); w != 12 {
+			t.Errorf("line %d %q has width %d, expected 12", i, l, w)
+		}
+	}
+	termdecotest.Golden(t, "box", s)
+}
+
+func TestBoxNarrowerThanTitle(t *testing.T) {
+	for _, align := range []Align{AlignLeft, AlignCenter, AlignRight} {
+		for width := 1; width <= 8; width++ {
+			b := &Box{Border: BoxASCII, Title: "title", TitleAlign: align, Width: width}
+			s := b.String("x")
+			expected := width
+			if expected < 3 {
+				expected = 3
+			}
+			for i, l := range termdeco.Split(s) {
+				if w := termdeco.Width(l); w != expected {
+					t.Errorf("align %d width %d: line %d %q has width %d, expected %d", align, width, i, l, w, expected)
+				}
+			}
+		}
+	}
+	b := &Box{Border: BoxASCII, Title: "title", Width: 5}
+	if top := termdeco.Split(b.String("x"))[0]; top != "+ … +" {
+		t.Errorf("top = %q, expected %q", top, "+ … +")
+	}
+}
+
+func TestBoxAmbiguousWide(t *testing.T) {
+	defer func(w int) { termdeco.AmbiguousWidth = w }(termdeco.AmbiguousWidth)
+	termdeco.AmbiguousWidth = 2
+	b := NewBox()
+	b.Title = "t"
+	expected := "" +
+		"╭─ t  ─╮\n" +
+		"│ abcde  │\n" +
+		"╰────╯"
+	s := b.String("abcde")
+	if s != expected {
+		t.Errorf("String =\n%s\nexpected\n%s", s, expected)
+	}
+	for i, l := range termdeco.Split(s) {
+		if w := termdeco.Width(l); w != 12 {
+			t.Errorf("line %d %q has width %d, expected 12", i, l, w)
+		}
+	}
+
+	// a fixed width may leave a column which no glyph fits
+	b.Width = 11
+	for i, l := range termdeco.Split(b.String("abcde")) {
+		if w := termdeco.Width(l); w != 11 {
+			t.Errorf("width 11: line %d %q has width %d", i, l, w)
+		}
+	}
+	if w := termdeco.Width(NewRule("x").String(9)); w != 9 {
+		t.Errorf("rule width = %d, expected 9", w)
+	}
+}
@@ -0,0 +1,152 @@
+package layout
+
+import (
+	"io"
+	"strings"
+
+	"github.com/tatsushid/termdeco"
+)
+
+// VAlign is vertical alignment.
+type VAlign int
+
+const (
+	AlignTop VAlign = iota
+	AlignMiddle
+	AlignBottom
+)
+
+// block is lines of a multi-line string and its width.
+type block struct {
+	lines []string
+	width int
+}
+
+func newBlock(s string) block {
+	b := block{lines: termdeco.Split(s)}
+	for _, l := range b.lines {
+		if w := termdeco.Width(l); w > b.width {
+			b.width = w
+		}
+	}
+	return b
+}
+
+// JoinHorizontal places multi-line blocks side by side, aligned by valign.
+// Each block is padded with spaces to a rectangle, so a block's styles don't
+// leak into its neighbors and the result is also a rectangle.
+func JoinHorizontal(valign VAlign, blocks ...string) string {
+	bs := make([]block, len(blocks))
+	height := 0
+	for i, s := range blocks {
+		bs[i] = newBlock(s)
+		if len(bs[i].lines) > height {
+			height = len(bs[i].lines)
+		}
+	}
+	lines := make([]string, height)
+	for _, b := range bs {
+		top := 0
+		switch valign {
+		case AlignMiddle:
+			top = (height - len(b.lines)) / 2
+		case AlignBottom:
+			top = height - len(b.lines)
+		}
+		for i := range lines {
+			l := ""
+			if i >= top && i-top < len(b.lines) {
+				l = b.lines[i-top]
+			}
+			lines[i] += termdeco.PadRight(l, b.width)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// JoinVertical stacks multi-line blocks, aligning their lines by align in the
+// width of the widest block.
+func JoinVertical(align Align, blocks ...string) string {
+	bs := make([]block, len(blocks))
+	width := 0
+	for i, s := range blocks {
+		bs[i] = newBlock(s)
+		if bs[i].width > width {
+			width = bs[i].width
+		}
+	}
+	var lines []string
+	for _, b := range bs {
+		for _, l := range b.lines {
+			switch align {
+			case AlignCenter:
+				l = termdeco.Center(l, width)
+			case AlignRight:
+				l = termdeco.PadLeft(l, width)
+			default:
+				l = termdeco.PadRight(l, width)
+			}
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Grid packs items into as many columns as fit into width like ls. Items are
+// placed from top to bottom and then left to right, and columns are
+// separated by at least gap spaces. An item wider than width is put on its
+// own line.
+func Grid(items []string, width, gap int) string {
+	if len(items) == 0 {
+		return ""
+	}
+	widths := make([]int, len(items))
+	for i, it := range items {
+		widths[i] = termdeco.Width(it)
+	}
+
+	rows := len(items)
+	var colWidths []int
+	for r := 1; r <= len(items); r++ {
+		cols := (len(items) + r - 1) / r
+		cw := make([]int, cols)
+		for i, w := range widths {
+			if c := i / r; w > cw[c] {
+				cw[c] = w
+			}
+		}
+		total := gap * (cols - 1)
+		for _, w := range cw {
+			total += w
+		}
+		if total <= width || cols == 1 {
+			rows, colWidths = r, cw
+			break
+		}
+	}
+
+	lines := make([]string, rows)
+	for i, it := range items {
+		r, c := i%rows, i/rows
+		if c > 0 {
+			lines[r] += strings.Repeat(" ", gap)
+		}
+		// the last item of a line isn't padded
+		if i+rows < len(items) {
+			it = termdeco.PadRight(it, colWidths[c])
+		}
+		lines[r] += it
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RenderGrid writes items packed into columns by Grid in the width of w's
+// terminal, or DefaultWidth if it is unknown, with two spaces of gap.
+func RenderGrid(w io.Writer, items []string) error {
+	width := DefaultWidth
+	if cols, _, err := termdeco.Size(w); err == nil {
+		width = cols
+	}
+	_, err := termdeco.Fprintln(w, Grid(items, width, 2))
+	return err
+}
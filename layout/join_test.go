@@ -0,0 +1,64 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/tatsushid/termdeco"
+	"github.com/tatsushid/termdeco/termdecotest"
+)
+
+func TestJoinHorizontal(t *testing.T) {
+	a := "a\nbb\nccc"
+	b := "日本"
+	tests := []struct {
+		valign   VAlign
+		expected string
+	}{
+		{AlignTop, "a  日本\nbb     \nccc    "},
+		{AlignMiddle, "a      \nbb 日本\nccc    "},
+		{AlignBottom, "a      \nbb     \nccc日本"},
+	}
+	for _, tt := range tests {
+		if s := JoinHorizontal(tt.valign, a, b); s != tt.expected {
+			t.Errorf("JoinHorizontal(%v) = %q, expected %q", tt.valign, s, tt.expected)
+		}
+	}
+
+	red := termdeco.Sprint(termdeco.Red("x\ny").PerLine(true))
+	s := JoinHorizontal(AlignTop, red, "|")
+	termdecotest.AssertClean(t, s)
+	termdecotest.AssertStyled(t, s, termdeco.Sprint(termdeco.Red("x"))+"|\n"+termdeco.Sprint(termdeco.Red("y"))+" ")
+}
+
+func TestJoinVertical(t *testing.T) {
+	tests := []struct {
+		align    Align
+		expected string
+	}{
+		{AlignLeft, "abcd\nx   \n日  "},
+		{AlignCenter, "abcd\n x  \n 日 "},
+		{AlignRight, "abcd\n   x\n  日"},
+	}
+	for _, tt := range tests {
+		if s := JoinVertical(tt.align, "abcd", "x\n日"); s != tt.expected {
+			t.Errorf("JoinVertical(%v) = %q, expected %q", tt.align, s, tt.expected)
+		}
+	}
+}
+
+func TestGrid(t *testing.T) {
+	items := []string{"alpha", "b", "charlie", "d", "echo", "f", "日本語"}
+	tests := []struct {
+		width    int
+		expected string
+	}{
+		{80, "alpha  b  charlie  d  echo  f  日本語"},
+		{20, "alpha    echo\nb        f\ncharlie  日本語\nd"},
+		{5, "alpha\nb\ncharlie\nd\necho\nf\n日本語"},
+	}
+	for _, tt := range tests {
+		if s := Grid(items, tt.width, 2); s != tt.expected {
+			t.Errorf("Grid(%d) =\n%s\nexpected\n%s", tt.width, s, tt.expected)
+		}
+	}
+}
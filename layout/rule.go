@@ -0,0 +1,77 @@
+package layout
+
+import (
+	"io"
+
+	"github.com/tatsushid/termdeco"
+)
+
+// DefaultWidth is the width used when the width of the terminal is unknown.
+var DefaultWidth = 80
+
+// Rule is a horizontal line with an optional label like
+//
+//	────────────── Summary ──────────────
+type Rule struct {
+	// Glyph is repeated to draw the line. It is measured by Width, and if it
+	// occupies two columns, an odd column at the end is left blank.
+	Glyph string
+	Style *termdeco.Decorator
+	// Label is printed in the line if it isn't nil.
+	Label interface{}
+	Align Align
+}
+
+// NewRule returns a Rule of "─" with label centered.
+func NewRule(label interface{}) *Rule {
+	return &Rule{Glyph: "─", Label: label, Align: AlignCenter}
+}
+
+// String returns the rule of width columns.
+func (r *Rule) String(width int) string {
+	glyph := r.Glyph
+	if glyph == "" {
+		glyph = "─"
+	}
+	if width <= 0 {
+		return ""
+	}
+	if r.Label == nil {
+		return apply(r.Style, repeat(glyph, width))
+	}
+	label := termdeco.Sprint(r.Label)
+	if width < 4 {
+		return apply(r.Style, repeat(glyph, width))
+	}
+	label = " " + termdeco.Truncate(label, width-4, "…") + " "
+	rest := width - termdeco.Width(label)
+	gw := glyphWidth(glyph)
+	var before int
+	switch r.Align {
+	case AlignCenter:
+		before = rest / 2
+	case AlignRight:
+		before = rest - gw
+	default:
+		before = gw
+	}
+	if before > rest {
+		before = rest
+	}
+	if before < 0 {
+		before = 0
+	}
+	before -= before % gw
+	return apply(r.Style, repeat(glyph, before)) + label + apply(r.Style, repeat(glyph, rest-before))
+}
+
+// Render writes the rule of the full width of w's terminal, or DefaultWidth
+// if it is unknown, to w.
+func (r *Rule) Render(w io.Writer) error {
+	width := DefaultWidth
+	if cols, _, err := termdeco.Size(w); err == nil {
+		width = cols
+	}
+	_, err := termdeco.Fprintln(w, r.String(width))
+	return err
+}
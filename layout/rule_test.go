@@ -0,0 +1,22 @@
+package layout
+
+import "testing"
+
+func TestRule(t *testing.T) {
+	tests := []struct {
+		r        *Rule
+		width    int
+		expected string
+	}{
+		{NewRule(nil), 5, "─────"},
+		{NewRule("Summary"), 15, "─── Summary ───"},
+		{NewRule("日本"), 10, "── 日本 ──"},
+		{&Rule{Glyph: "=", Label: "x", Align: AlignRight}, 8, "==== x ="},
+		{NewRule("too long label"), 10, "─ too l… ─"},
+	}
+	for _, tt := range tests {
+		if s := tt.r.String(tt.width); s != tt.expected {
+			t.Errorf("String(%d) = %q, expected %q", tt.width, s, tt.expected)
+		}
+	}
+}
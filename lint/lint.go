@@ -0,0 +1,246 @@
+// lint is a package to find problems of escape sequences in terminal output,
+// like a missing reset which leaves the user's prompt colored.
+//
+//	for _, p := range lint.Check(output) {
+//		fmt.Println(p) // 1:6: style leaks past end of output
+//	}
+package lint
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tatsushid/termdeco"
+	"github.com/tatsushid/termdeco/vt"
+)
+
+// Kind is a kind of Problem.
+type Kind int
+
+const (
+	// Unterminated is an escape sequence cut by the end of output or an
+	// unexpected character.
+	Unterminated Kind = iota
+	// UnknownSGR is an SGR sequence with an unknown or malformed parameter.
+	UnknownSGR
+	// UnmatchedReset is an SGR sequence resetting styles when no style is
+	// active.
+	UnmatchedReset
+	// LineLeak is a style active at the end of a line.
+	LineLeak
+	// OutputLeak is a style active at the end of output.
+	OutputLeak
+	// UnexpectedControl is a control character other than newline,
+	// carriage return, tab and backspace.
+	UnexpectedControl
+	// LinkLeak is an OSC 8 hyperlink open at the end of output.
+	LinkLeak
+)
+
+var kindNames = [...]string{
+	"unterminated", "unknown-sgr", "unmatched-reset", "line-leak", "output-leak", "unexpected-control", "link-leak",
+}
+
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Problem is a problem found in output.
+type Problem struct {
+	Kind Kind
+	// Offset is the byte offset of the problem in the output.
+	Offset int
+	// Line and Column are the 1-based position of the problem. Column
+	// counts characters, not bytes.
+	Line, Column int
+	Message      string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+}
+
+// Linter is an io.Writer which checks output written to it. Close must be
+// called at the end of output to check styles leaking out of it.
+type Linter struct {
+	problems     []Problem
+	style        vt.Style
+	link         string
+	offset       int
+	line, column int
+	pending      []byte
+	closed       bool
+}
+
+// Check returns problems found in s.
+func Check(s string) []Problem {
+	l := &Linter{}
+	l.Write([]byte(s))
+	l.Close()
+	return l.Problems()
+}
+
+// Problems returns problems found so far.
+func (l *Linter) Problems() []Problem {
+	return l.problems
+}
+
+func (l *Linter) report(kind Kind, format string, a ...interface{}) {
+	l.problems = append(l.problems, Problem{
+		Kind:    kind,
+		Offset:  l.offset,
+		Line:    l.line + 1,
+		Column:  l.column + 1,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+// Write checks p. An escape sequence split across writes is checked when it
+// is completed. It never returns an error.
+func (l *Linter) Write(p []byte) (n int, err error) {
+	buf := string(append(l.pending, p...))
+	l.pending = nil
+	for buf != "" {
+		t := termdeco.NextToken(buf)
+		if t.Incomplete && len(t.Raw) == len(buf) {
+			l.pending = []byte(buf)
+			break
+		}
+		l.token(t)
+		l.offset += len(t.Raw)
+		buf = buf[len(t.Raw):]
+	}
+	return len(p), nil
+}
+
+// Close checks the end of output. It never returns an error.
+func (l *Linter) Close() error {
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	if len(l.pending) > 0 {
+		l.token(termdeco.NextToken(string(l.pending)))
+		l.offset += len(l.pending)
+		l.pending = nil
+	}
+	if l.style != (vt.Style{}) {
+		l.report(OutputLeak, "style %q leaks past end of output", l.style)
+	}
+	if l.link != "" {
+		l.report(LinkLeak, "hyperlink %q leaks past end of output", l.link)
+	}
+	return nil
+}
+
+var sequenceNames = map[termdeco.TokenKind]string{
+	termdeco.EscapeToken: "escape sequence",
+	termdeco.CSIToken:    "CSI sequence",
+	termdeco.OSCToken:    "OSC sequence",
+	termdeco.StringToken: "control string",
+}
+
+func (l *Linter) token(t termdeco.Token) {
+	if t.Incomplete {
+		l.report(Unterminated, "unterminated %s %q", sequenceNames[t.Kind], t.Raw)
+		return
+	}
+	switch t.Kind {
+	case termdeco.TextToken:
+		l.column += utf8.RuneCountInString(t.Raw)
+	case termdeco.ControlToken:
+		r, _ := utf8.DecodeRuneInString(t.Raw)
+		switch r {
+		case '\n':
+			if l.style != (vt.Style{}) {
+				l.report(LineLeak, "style %q leaks past end of line", l.style)
+			}
+			l.line++
+			l.column = 0
+		case '\r':
+			l.column = 0
+		case '\t', '\b':
+		default:
+			l.report(UnexpectedControl, "unexpected control character %U", r)
+		}
+	case termdeco.CSIToken:
+		if t.IsSGR() {
+			l.sgr(t)
+		}
+	case termdeco.OSCToken:
+		l.osc(t)
+	}
+}
+
+// osc tracks the hyperlink opened and closed by OSC 8 sequences like
+// ESC ] 8 ; params ; uri ST. A sequence with an empty uri closes it.
+func (l *Linter) osc(t termdeco.Token) {
+	body := strings.TrimPrefix(t.Raw, "\x1b]")
+	body = strings.TrimSuffix(strings.TrimSuffix(body, "\a"), "\x1b\\")
+	parts := strings.SplitN(body, ";", 3)
+	if parts[0] != "8" || len(parts) < 3 {
+		return
+	}
+	l.link = parts[2]
+}
+
+func (l *Linter) sgr(t termdeco.Token) {
+	params := t.Params()
+	if msg := checkSGR(params); msg != "" {
+		l.report(UnknownSGR, "%s in %q", msg, t.Raw)
+	}
+	if l.style == (vt.Style{}) && isReset(params) {
+		l.report(UnmatchedReset, "reset %q without active style", t.Raw)
+	}
+	l.style = l.style.ApplySGR(params)
+}
+
+// isReset reports whether params only reset styles.
+func isReset(params []int) bool {
+	for _, p := range params {
+		switch {
+		case p == 0, p >= 22 && p <= 29, p == 39, p == 49, p == 54, p == 55, p == 59:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// checkSGR returns a message describing the first unknown or malformed
+// parameter in params. It returns "" if all of them are known.
+func checkSGR(params []int) string {
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		switch {
+		case p >= 0 && p <= 9, p == 21, p >= 22 && p <= 29,
+			p >= 30 && p <= 37, p == 39, p >= 40 && p <= 47, p == 49,
+			p >= 53 && p <= 55, p == 59, p >= 90 && p <= 97, p >= 100 && p <= 107:
+		case p == 38 || p == 48 || p == 58:
+			if i+1 >= len(params) {
+				return fmt.Sprintf("missing color type after %d", p)
+			}
+			switch params[i+1] {
+			case 5:
+				if i+2 >= len(params) || params[i+2] > 255 {
+					return fmt.Sprintf("invalid 256 color for %d", p)
+				}
+				i += 2
+			case 2:
+				if i+4 >= len(params) || params[i+2] > 255 || params[i+3] > 255 || params[i+4] > 255 {
+					return fmt.Sprintf("invalid 24-bit color for %d", p)
+				}
+				i += 4
+			default:
+				return fmt.Sprintf("unknown color type %d after %d", params[i+1], p)
+			}
+		default:
+			return fmt.Sprintf("unknown parameter %d", p)
+		}
+	}
+	return ""
+}
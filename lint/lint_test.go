@@ -0,0 +1,56 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/tatsushid/termdeco"
+)
+
+func TestCheckClean(t *testing.T) {
+	s := termdeco.Sprintln("ok:", termdeco.Green("passed").Bold(), "\tin", termdeco.Yellow(3), "s")
+	if ps := Check(s); len(ps) != 0 {
+		t.Errorf("Check(%q) = %v, expected no problem", s, ps)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		s        string
+		kind     Kind
+		line     int
+		column   int
+		expected string
+	}{
+		{"a\x1b[31mb", OutputLeak, 1, 3, `1:3: style "fg:red" leaks past end of output`},
+		{"\x1b[1mab\nc\x1b[0m", LineLeak, 1, 3, `1:3: style "bold" leaks past end of line`},
+		{"x\x1b[0m", UnmatchedReset, 1, 2, `1:2: reset "\x1b[0m" without active style`},
+		{"\x1b[31;66mx\x1b[0m", UnknownSGR, 1, 1, `1:1: unknown parameter 66 in "\x1b[31;66m"`},
+		{"\x1b[38;5;300mx\x1b[0m", UnknownSGR, 1, 1, `1:1: invalid 256 color for 38 in "\x1b[38;5;300m"`},
+		{"\n\nab\x07", UnexpectedControl, 3, 3, "3:3: unexpected control character U+0007"},
+		{"ab\x1b]0;title", Unterminated, 1, 3, `1:3: unterminated OSC sequence "\x1b]0;title"`},
+		{"\x1b[3\nx", Unterminated, 1, 1, `1:1: unterminated CSI sequence "\x1b[3"`},
+		{"\x1b]8;;http://a\x1b\\ab", LinkLeak, 1, 3, `1:3: hyperlink "http://a" leaks past end of output`},
+	}
+	for _, tt := range tests {
+		ps := Check(tt.s)
+		if len(ps) != 1 {
+			t.Errorf("Check(%q) = %v, expected 1 problem", tt.s, ps)
+			continue
+		}
+		p := ps[0]
+		if p.Kind != tt.kind || p.Line != tt.line || p.Column != tt.column || p.String() != tt.expected {
+			t.Errorf("Check(%q) = %v (%v), expected %q (%v)", tt.s, p, p.Kind, tt.expected, tt.kind)
+		}
+	}
+}
+
+func TestLinterSplitWrite(t *testing.T) {
+	l := &Linter{}
+	l.Write([]byte("ab\x1b[3"))
+	l.Write([]byte("1mc\x1b[0"))
+	l.Write([]byte("m"))
+	l.Close()
+	if ps := l.Problems(); len(ps) != 0 {
+		t.Errorf("Problems = %v, expected no problem", ps)
+	}
+}
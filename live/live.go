@@ -0,0 +1,170 @@
+// live is a package of a live area, lines at the bottom of a terminal which
+// are redrawn in place while log output scrolls above them.
+//
+//	a := live.New(os.Stderr)
+//	defer a.Close()
+//	log.SetOutput(a)
+//	for i, job := range jobs {
+//		go job.Run(func(status string) { a.SetLine(i, status) })
+//	}
+//
+// It is for dashboards of concurrent tasks, where each task owns a status
+// line and their outputs must not be interleaved.
+package live
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/tatsushid/termdeco"
+)
+
+const (
+	eraseLine = "\x1b[2K"
+	eraseDown = "\x1b[J"
+	syncStart = "\x1b[?2026h"
+	syncEnd   = "\x1b[?2026l"
+)
+
+// Area is a live area. It is an io.Writer for log output which is printed
+// above the area. Its methods are safe for concurrent use.
+type Area struct {
+	// Synchronized wraps each redraw in synchronized output mode 2026, so
+	// terminals supporting it show redraws without flicker.
+	Synchronized bool
+	// Interactive is whether the area is redrawn in place. New sets it if
+	// the output is a terminal. Otherwise log output is written as is and
+	// the area is written only by Close.
+	Interactive bool
+
+	mu      sync.Mutex
+	w       io.Writer
+	lines   []string
+	drawn   int
+	partial []byte
+	closed  bool
+}
+
+// New returns an Area at the bottom of the terminal w writes to.
+func New(w io.Writer) *Area {
+	return &Area{
+		Interactive: termdeco.IsTerminal(w),
+		w:           w,
+	}
+}
+
+// Set replaces all lines of the area.
+func (a *Area) Set(lines ...string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lines = append(a.lines[:0], lines...)
+	a.redraw(nil)
+}
+
+// SetLine replaces the i-th line of the area, adding empty lines if the area
+// has less lines.
+func (a *Area) SetLine(i int, s string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for len(a.lines) <= i {
+		a.lines = append(a.lines, "")
+	}
+	a.lines[i] = s
+	a.redraw(nil)
+}
+
+// Write prints p above the area. An incomplete last line is held until its
+// newline or Close.
+func (a *Area) Write(p []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed || !a.Interactive {
+		_, err := termdeco.Fprint(a.w, string(p))
+		return len(p), err
+	}
+	a.partial = append(a.partial, p...)
+	i := bytes.LastIndexByte(a.partial, '\n')
+	if i < 0 {
+		return len(p), nil
+	}
+	logs := string(a.partial[:i])
+	a.partial = append(a.partial[:0], a.partial[i+1:]...)
+	return len(p), a.redraw(termdeco.Split(logs))
+}
+
+// Close prints the held log output and leaves the final lines of the area on
+// the terminal. The area isn't redrawn after it and log output is written as
+// is.
+func (a *Area) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return nil
+	}
+	var logs []string
+	if len(a.partial) > 0 {
+		logs = termdeco.Split(string(a.partial))
+		a.partial = nil
+	}
+	var err error
+	if a.Interactive {
+		err = a.redraw(logs)
+	} else {
+		var b strings.Builder
+		for _, l := range append(logs, a.lines...) {
+			b.WriteString(l + "\n")
+		}
+		_, err = termdeco.Fprint(a.w, b.String())
+	}
+	a.closed = true
+	return err
+}
+
+// redraw prints logs over the area and the area below them. The cursor is
+// at the start of the line below the area after it.
+func (a *Area) redraw(logs []string) error {
+	if a.closed || !a.Interactive {
+		return nil
+	}
+	cols, rows := 0, 0
+	if c, r, err := termdeco.Size(a.w); err == nil {
+		cols, rows = c, r
+	}
+
+	var b strings.Builder
+	if a.Synchronized {
+		b.WriteString(syncStart)
+	}
+	if a.drawn > 0 {
+		fmt.Fprintf(&b, "\r\x1b[%dA", a.drawn)
+	}
+	for _, l := range logs {
+		b.WriteString("\r" + eraseLine + l + "\n")
+	}
+	var lines []string
+	for _, s := range a.lines {
+		lines = append(lines, termdeco.Split(s)...)
+	}
+	// lines scrolled out of the screen can't be redrawn, so ones beyond
+	// the rows above the cursor are dropped
+	if rows > 0 && len(lines) > rows-1 {
+		lines = lines[:rows-1]
+	}
+	for _, l := range lines {
+		// a wrapped line would make the area taller than counted
+		if cols > 0 {
+			l = termdeco.Truncate(l, cols, "")
+		}
+		b.WriteString("\r" + eraseLine + l + "\n")
+	}
+	b.WriteString("\r" + eraseDown)
+	if a.Synchronized {
+		b.WriteString(syncEnd)
+	}
+	a.drawn = len(lines)
+	_, err := termdeco.Fprint(a.w, b.String())
+	return err
+}
@@ -0,0 +1,138 @@
+package live
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tatsushid/termdeco"
+	"github.com/tatsushid/termdeco/vt"
+)
+
+// newScreenArea returns an Area on a screen of 6 rows. The area takes a row
+// below its lines for the cursor.
+func newScreenArea() (*Area, *vt.Screen) {
+	s := vt.New(20, 6)
+	a := New(s)
+	a.Interactive = true
+	return a, s
+}
+
+func TestArea(t *testing.T) {
+	a, s := newScreenArea()
+	a.Set("job1: running", "job2: running")
+	fmt.Fprintln(a, "log 1")
+	a.SetLine(1, termdeco.Sprint(termdeco.Green("job2: done")))
+	fmt.Fprint(a, "log 2a ")
+	fmt.Fprintln(a, "log 2b\nlog 3")
+	a.SetLine(0, "job1: done")
+
+	expected := "log 1\nlog 2a log 2b\nlog 3\njob1: done\njob2: done"
+	if got := s.String(); got != expected {
+		t.Errorf("screen =\n%s\nexpected\n%s", got, expected)
+	}
+	if c := s.Cell(0, 4); c.Style.Fg != vt.IndexedColor(2) {
+		t.Errorf("style of job2 line = %v, expected green", c.Style)
+	}
+
+	// shrinking the area erases the rest
+	a.Set("all done")
+	if got := s.String(); got != "log 1\nlog 2a log 2b\nlog 3\nall done" {
+		t.Errorf("screen after shrinking =\n%s", got)
+	}
+}
+
+func TestAreaScroll(t *testing.T) {
+	a, s := newScreenArea()
+	a.Set("status")
+	for i := 1; i <= 6; i++ {
+		fmt.Fprintf(a, "log %d\n", i)
+	}
+	a.Close()
+	expected := "log 3\nlog 4\nlog 5\nlog 6\nstatus"
+	if got := s.String(); got != expected {
+		t.Errorf("screen =\n%s\nexpected\n%s", got, expected)
+	}
+	if sb := s.Scrollback(); len(sb) != 2 || sb[0] != "log 1" || sb[1] != "log 2" {
+		t.Errorf("scrollback = %q, expected log 1 and log 2 without status lines", sb)
+	}
+}
+
+func TestAreaTruncateAndSync(t *testing.T) {
+	t.Setenv("COLUMNS", "20")
+	t.Setenv("LINES", "5")
+	var buf bytes.Buffer
+	a := New(&buf)
+	a.Interactive = true
+	a.Synchronized = true
+	a.Set(strings.Repeat("x", 30))
+	out := buf.String()
+	if !strings.HasPrefix(out, "\x1b[?2026h") || !strings.HasSuffix(out, "\x1b[?2026l") {
+		t.Errorf("output = %q, expected to be synchronized", out)
+	}
+	s := vt.New(20, 5)
+	s.Write(buf.Bytes())
+	a.SetLine(1, "second")
+	s.Write(buf.Bytes()[len(out):])
+	if got := s.String(); got != strings.Repeat("x", 20)+"\nsecond" {
+		t.Errorf("screen =\n%s", got)
+	}
+}
+
+func TestAreaTallerThanScreen(t *testing.T) {
+	t.Setenv("COLUMNS", "20")
+	t.Setenv("LINES", "5")
+	s := vt.New(20, 5)
+	a := New(s)
+	a.Interactive = true
+	var lines []string
+	for i := 0; i < 8; i++ {
+		lines = append(lines, fmt.Sprintf("job%d", i))
+	}
+	a.Set(lines...)
+	a.SetLine(1, "job1: done")
+	fmt.Fprintln(a, "log")
+	a.SetLine(7, "job7: done")
+
+	expected := "job0\njob1: done\njob2\njob3"
+	if got := s.String(); got != expected {
+		t.Errorf("screen =\n%s\nexpected\n%s", got, expected)
+	}
+	if sb := s.Scrollback(); len(sb) != 1 || sb[0] != "log" {
+		t.Errorf("scrollback = %q, expected only the log line", sb)
+	}
+}
+
+func TestAreaConcurrent(t *testing.T) {
+	a, s := newScreenArea()
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j <= 50; j++ {
+				a.SetLine(i, fmt.Sprintf("job%d: %d%%", i, j*2))
+			}
+		}(i)
+	}
+	wg.Wait()
+	a.Close()
+	expected := "job0: 100%\njob1: 100%\njob2: 100%"
+	if got := s.String(); got != expected {
+		t.Errorf("screen =\n%q\nexpected\n%q", got, expected)
+	}
+}
+
+func TestAreaNotInteractive(t *testing.T) {
+	var buf bytes.Buffer
+	a := New(&buf)
+	a.Set("status 1")
+	fmt.Fprintln(a, "log")
+	a.Set("status 2")
+	a.Close()
+	if buf.String() != "log\nstatus 2\n" {
+		t.Errorf("output = %q", buf.String())
+	}
+}
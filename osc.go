@@ -0,0 +1,38 @@
+package termdeco
+
+import (
+	"os"
+	"strings"
+)
+
+const (
+	oscStart = "\x1b]"
+	oscEnd   = "\x1b\\"
+	bel      = "\a"
+)
+
+// screenChunk is the length of pieces of a sequence passed through GNU
+// screen, which drops long control strings.
+const screenChunk = 76
+
+// passthrough wraps seq so it reaches the outer terminal through tmux or GNU
+// screen detected from the environment. tmux needs allow-passthrough on to
+// pass it.
+func passthrough(seq string) string {
+	switch {
+	case os.Getenv("TMUX") != "":
+		return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + oscEnd
+	case os.Getenv("STY") != "" || strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		var b strings.Builder
+		for seq != "" {
+			n := screenChunk
+			if n > len(seq) {
+				n = len(seq)
+			}
+			b.WriteString("\x1bP" + seq[:n] + oscEnd)
+			seq = seq[n:]
+		}
+		return b.String()
+	}
+	return seq
+}
@@ -0,0 +1,441 @@
+// progress is a package of progress bars decorated by termdeco.
+//
+//	bar := progress.New(os.Stderr, size)
+//	bar.Label = "downloading"
+//	bar.Format = progress.FormatBytes
+//	io.Copy(f, bar.Reader(resp.Body))
+//	bar.Finish()
+//
+// On a terminal, a bar is redrawn in place. Otherwise it writes plain log
+// lines periodically, so logs of CI jobs aren't flooded with redraws.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/tatsushid/termdeco"
+)
+
+const (
+	// DefaultTemplate is the template of determinate bars.
+	DefaultTemplate = "{{.Label}} {{.Bar}} {{.Percent}} {{.Current}}/{{.Total}} {{.Rate}} ETA {{.ETA}}"
+	// DefaultIndeterminateTemplate is the template of bars without total.
+	DefaultIndeterminateTemplate = "{{.Label}} {{.Bar}} {{.Current}} {{.Rate}} {{.Elapsed}}"
+	// DefaultLogTemplate is the template of log lines of determinate bars.
+	DefaultLogTemplate = "{{.Label}} {{.Percent}} {{.Current}}/{{.Total}} {{.Rate}} ETA {{.ETA}}"
+	// DefaultIndeterminateLogTemplate is the template of log lines of bars
+	// without total.
+	DefaultIndeterminateLogTemplate = "{{.Label}} {{.Current}} {{.Rate}} elapsed {{.Elapsed}}"
+)
+
+// Stats is values given to templates. All of them are formatted strings.
+type Stats struct {
+	Label   string
+	Bar     string
+	Percent string
+	Current string
+	Total   string
+	Rate    string
+	ETA     string
+	Elapsed string
+}
+
+// Threshold is a style of the fill used from a percentage.
+type Threshold struct {
+	Percent float64
+	Style   *termdeco.Decorator
+}
+
+// Bar is a progress bar. Its methods are safe for concurrent use.
+type Bar struct {
+	// Total is the amount of the work. If it is 0 or less, the bar is
+	// indeterminate and shows a bouncing block.
+	Total int64
+	Label string
+	// Width is the number of columns of the bar itself.
+	Width int
+	// Fill and Empty are glyphs of done and remaining parts of the bar.
+	Fill, Empty string
+	// Style is the style of the fill. It is overridden by the last of
+	// Thresholds whose Percent is reached and by Gradient.
+	Style      *termdeco.Decorator
+	Thresholds []Threshold
+	// Gradient is styles of the fill from the left to the right end.
+	Gradient   []*termdeco.Decorator
+	EmptyStyle *termdeco.Decorator
+	// Template and LogTemplate are text/template templates of Stats for
+	// a terminal and for log lines. The defaults are used if they are
+	// empty.
+	Template, LogTemplate string
+	// Format formats amounts like Current and Total.
+	Format func(n int64) string
+	// Interactive is whether the bar is redrawn in place. New sets it if
+	// the output is a terminal.
+	Interactive bool
+	// RefreshInterval is the minimum interval of redraws on a terminal
+	// and LogInterval is the interval of log lines. The block of an
+	// indeterminate bar on a terminal moves a column every RefreshInterval
+	// and the bar is redrawn by then even if the amount isn't updated.
+	RefreshInterval, LogInterval time.Duration
+	// Now returns the current time. It can be replaced in tests.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	w        io.Writer
+	current  int64
+	start    time.Time
+	lastDraw time.Time
+	done     bool
+	// tmpls is parsed templates by their text.
+	tmpls map[string]*template.Template
+	// stop and stopped are channels to stop the goroutine redrawing an
+	// indeterminate bar.
+	stop, stopped chan struct{}
+	// newTicker returns a channel ticking every d and a function to stop
+	// it. time.NewTicker is used if it is nil. It can be replaced in tests.
+	newTicker func(d time.Duration) (<-chan time.Time, func())
+}
+
+// defaultRefreshInterval is the RefreshInterval set by New. It's also used if
+// RefreshInterval is 0 or less to move the block of an indeterminate bar.
+const defaultRefreshInterval = 100 * time.Millisecond
+
+// New returns a Bar writing to w for total amount of work.
+func New(w io.Writer, total int64) *Bar {
+	return &Bar{
+		Total:           total,
+		Width:           30,
+		Fill:            "█",
+		Empty:           "░",
+		EmptyStyle:      termdeco.BrightBlack(nil),
+		Format:          FormatNumber,
+		Interactive:     termdeco.IsTerminal(w),
+		RefreshInterval: defaultRefreshInterval,
+		LogInterval:     5 * time.Second,
+		Now:             time.Now,
+		w:               w,
+	}
+}
+
+// Add adds n to the current amount and redraws the bar.
+func (b *Bar) Add(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.set(b.current+n, false)
+}
+
+// Set sets the current amount to n and redraws the bar.
+func (b *Bar) Set(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.set(n, false)
+}
+
+// Finish draws the bar for the last time. The bar isn't updated after it.
+func (b *Bar) Finish() {
+	b.mu.Lock()
+	if b.done {
+		b.mu.Unlock()
+		return
+	}
+	b.set(b.current, true)
+	if b.Interactive {
+		termdeco.Fprint(b.w, "\n")
+	}
+	b.done = true
+	stop, stopped := b.stop, b.stopped
+	b.stop, b.stopped = nil, nil
+	b.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-stopped
+	}
+}
+
+// String returns the current line of the bar for a terminal.
+func (b *Bar) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.render(b.now(), true)
+}
+
+func (b *Bar) now() time.Time {
+	if b.Now != nil {
+		return b.Now()
+	}
+	return time.Now()
+}
+
+func (b *Bar) set(n int64, force bool) {
+	if b.done {
+		return
+	}
+	now := b.now()
+	if b.start.IsZero() {
+		b.start = now
+		if b.Interactive && b.Total <= 0 {
+			b.stop = make(chan struct{})
+			b.stopped = make(chan struct{})
+			c, stopTicker := b.ticker(b.refreshInterval())
+			go b.loop(c, stopTicker, b.stop, b.stopped)
+		}
+	}
+	b.current = n
+	if b.Interactive {
+		if !force && !b.lastDraw.IsZero() && now.Sub(b.lastDraw) < b.RefreshInterval {
+			return
+		}
+		line := b.render(now, true)
+		if cols, _, err := termdeco.Size(b.w); err == nil {
+			// a wrapped line can't be redrawn in place
+			line = termdeco.Truncate(line, cols-1, "")
+		}
+		termdeco.Fprint(b.w, "\r"+line+"\x1b[K")
+	} else {
+		if !force && !b.lastDraw.IsZero() && now.Sub(b.lastDraw) < b.LogInterval {
+			return
+		}
+		termdeco.Fprint(b.w, b.render(now, false)+"\n")
+	}
+	b.lastDraw = now
+}
+
+// refreshInterval returns RefreshInterval, or the default if it isn't
+// positive.
+func (b *Bar) refreshInterval() time.Duration {
+	if b.RefreshInterval <= 0 {
+		return defaultRefreshInterval
+	}
+	return b.RefreshInterval
+}
+
+// ticker returns a channel ticking every d and a function to stop it.
+func (b *Bar) ticker(d time.Duration) (<-chan time.Time, func()) {
+	if b.newTicker != nil {
+		return b.newTicker(d)
+	}
+	t := time.NewTicker(d)
+	return t.C, t.Stop
+}
+
+// loop redraws an indeterminate bar every tick of c, so its block keeps
+// moving while the amount isn't updated.
+func (b *Bar) loop(c <-chan time.Time, stopTicker func(), stop, stopped chan struct{}) {
+	defer close(stopped)
+	defer stopTicker()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-c:
+			b.mu.Lock()
+			b.set(b.current, true)
+			b.mu.Unlock()
+		}
+	}
+}
+
+func (b *Bar) render(now time.Time, interactive bool) string {
+	format := b.Format
+	if format == nil {
+		format = FormatNumber
+	}
+	determinate := b.Total > 0
+	st := Stats{
+		Label:   b.Label,
+		Current: format(b.current),
+		Total:   "?",
+		Rate:    "--/s",
+		ETA:     "--",
+	}
+	start := b.start
+	if start.IsZero() {
+		start = now
+	}
+	elapsed := now.Sub(start)
+	st.Elapsed = formatDuration(elapsed)
+	if elapsed > 0 {
+		rate := float64(b.current) / elapsed.Seconds()
+		st.Rate = format(int64(rate)) + "/s"
+		if determinate && rate > 0 {
+			st.ETA = formatDuration(time.Duration(float64(b.Total-b.current) / rate * float64(time.Second)))
+		}
+	}
+	if determinate {
+		st.Total = format(b.Total)
+		st.Percent = fmt.Sprintf("%3d%%", int(b.fraction()*100))
+	}
+	if interactive {
+		st.Bar = b.bar(elapsed)
+	}
+
+	tmpl := b.Template
+	if !interactive {
+		tmpl = b.LogTemplate
+	}
+	if tmpl == "" {
+		switch {
+		case interactive && determinate:
+			tmpl = DefaultTemplate
+		case interactive:
+			tmpl = DefaultIndeterminateTemplate
+		case determinate:
+			tmpl = DefaultLogTemplate
+		default:
+			tmpl = DefaultIndeterminateLogTemplate
+		}
+	}
+	t, err := b.parse(tmpl)
+	if err != nil {
+		return "progress: " + err.Error()
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, st); err != nil {
+		return "progress: " + err.Error()
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// parse returns the parsed template of text. Templates are parsed once and
+// reused by later redraws.
+func (b *Bar) parse(text string) (*template.Template, error) {
+	if t, ok := b.tmpls[text]; ok {
+		return t, nil
+	}
+	t, err := template.New("progress").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if b.tmpls == nil {
+		b.tmpls = make(map[string]*template.Template)
+	}
+	b.tmpls[text] = t
+	return t, nil
+}
+
+func (b *Bar) fraction() float64 {
+	if b.Total <= 0 {
+		return 0
+	}
+	f := float64(b.current) / float64(b.Total)
+	switch {
+	case f < 0:
+		return 0
+	case f > 1:
+		return 1
+	}
+	return f
+}
+
+// bounceWidth is the width of the block of an indeterminate bar.
+const bounceWidth = 3
+
+// bar returns the bar part of the line.
+func (b *Bar) bar(elapsed time.Duration) string {
+	width := b.Width
+	if width <= 0 {
+		width = 30
+	}
+	from, to := 0, int(b.fraction()*float64(width))
+	if b.Total <= 0 {
+		// the block moves a column per refresh and bounces at both ends
+		span := width - bounceWidth
+		if span <= 0 {
+			from, to = 0, width
+		} else {
+			pos := int(elapsed/b.refreshInterval()) % (2 * span)
+			if pos > span {
+				pos = 2*span - pos
+			}
+			from, to = pos, pos+bounceWidth
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(apply(b.EmptyStyle, strings.Repeat(b.Empty, from)))
+	style := b.Style
+	for _, t := range b.Thresholds {
+		if b.fraction()*100 >= t.Percent {
+			style = t.Style
+		}
+	}
+	if len(b.Gradient) > 0 {
+		for i := from; i < to; i++ {
+			sb.WriteString(apply(b.Gradient[i*len(b.Gradient)/width], b.Fill))
+		}
+	} else {
+		sb.WriteString(apply(style, strings.Repeat(b.Fill, to-from)))
+	}
+	sb.WriteString(apply(b.EmptyStyle, strings.Repeat(b.Empty, width-to)))
+	return sb.String()
+}
+
+func apply(d *termdeco.Decorator, s string) string {
+	if d == nil || s == "" {
+		return s
+	}
+	return d.Apply(s)
+}
+
+// FormatNumber formats n as a decimal number.
+func FormatNumber(n int64) string {
+	return fmt.Sprint(n)
+}
+
+// FormatBytes formats n as a size in bytes with binary prefixes like
+// "1.5 MiB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 5; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+type reader struct {
+	r io.Reader
+	b *Bar
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.b.Add(int64(n))
+	return n, err
+}
+
+// Reader returns a Reader which adds the number of bytes read from r to the
+// bar.
+func (b *Bar) Reader(r io.Reader) io.Reader {
+	return &reader{r, b}
+}
+
+type writer struct {
+	w io.Writer
+	b *Bar
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.b.Add(int64(n))
+	return n, err
+}
+
+// Writer returns a Writer which adds the number of bytes written to w to the
+// bar.
+func (b *Bar) Writer(w io.Writer) io.Writer {
+	return &writer{w, b}
+}
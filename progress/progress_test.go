@@ -0,0 +1,182 @@
+package progress
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tatsushid/termdeco"
+	"github.com/tatsushid/termdeco/termdecotest"
+)
+
+// clock is a fake clock and ticker advanced by tests.
+type clock struct {
+	mu    sync.Mutex
+	t     time.Time
+	ticks chan time.Time
+}
+
+func (c *clock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func (c *clock) ticker(time.Duration) (<-chan time.Time, func()) {
+	return c.ticks, func() {}
+}
+
+// tick fires the ticker and waits for the redraw by it. The second tick is
+// received only after the redraw by the first one.
+func (c *clock) tick() {
+	c.ticks <- c.now()
+	c.ticks <- c.now()
+}
+
+func newTestBar(w io.Writer, total int64, interactive bool) (*Bar, *clock) {
+	c := &clock{t: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), ticks: make(chan time.Time)}
+	b := New(w, total)
+	b.Interactive = interactive
+	b.Now = c.now
+	b.newTicker = c.ticker
+	b.Width = 10
+	b.EmptyStyle = nil
+	return b, c
+}
+
+func TestBarString(t *testing.T) {
+	b, c := newTestBar(io.Discard, 200, false)
+	b.Label = "copy"
+	b.Set(0)
+	c.advance(2 * time.Second)
+	b.Set(50)
+	if s := b.String(); s != "copy ██░░░░░░░░  25% 50/200 25/s ETA 6s" {
+		t.Errorf("String = %q", s)
+	}
+}
+
+func TestBarLog(t *testing.T) {
+	var buf bytes.Buffer
+	b, c := newTestBar(&buf, 100, false)
+	for i := 0; i < 10; i++ {
+		b.Add(10)
+		c.advance(time.Second)
+	}
+	b.Finish()
+	expected := "" +
+		"10% 10/100 --/s ETA --\n" +
+		"60% 60/100 12/s ETA 3s\n" +
+		"100% 100/100 10/s ETA 0s\n"
+	if buf.String() != expected {
+		t.Errorf("log =\n%s\nexpected\n%s", buf.String(), expected)
+	}
+}
+
+func TestBarInteractive(t *testing.T) {
+	var buf bytes.Buffer
+	b, c := newTestBar(&buf, 4, true)
+	b.Template = "{{.Bar}} {{.Percent}}"
+	b.Style = termdeco.Green(nil)
+	b.Thresholds = []Threshold{{Percent: 50, Style: termdeco.Yellow(nil)}}
+	b.Add(1)
+	b.Add(1) // throttled
+	c.advance(time.Second)
+	b.Add(1)
+	b.Finish()
+	b.Add(1) // ignored after Finish
+	lines := strings.Split(buf.String(), "\r")
+	if len(lines) != 4 || lines[0] != "" {
+		t.Fatalf("output = %q, expected 3 redraws", buf.String())
+	}
+	termdecotest.AssertStyled(t, lines[1], termdeco.Sprint(termdeco.Green("██"))+"░░░░░░░░  25%\x1b[K")
+	termdecotest.AssertStyled(t, lines[2], termdeco.Sprint(termdeco.Yellow("███████"))+"░░░  75%\x1b[K")
+	if !strings.HasSuffix(lines[3], "\x1b[K\n") {
+		t.Errorf("last redraw = %q, expected a newline at the end", lines[3])
+	}
+}
+
+func TestBarIndeterminateAndGradient(t *testing.T) {
+	b, c := newTestBar(io.Discard, 0, true)
+	b.Template = "{{.Bar}}"
+	defer b.Finish()
+	b.Set(1)
+	if s := b.String(); s != "███░░░░░░░" {
+		t.Errorf("String = %q", s)
+	}
+	c.advance(900 * time.Millisecond)
+	if s := b.String(); s != "░░░░░███░░" {
+		t.Errorf("String after bouncing = %q", s)
+	}
+
+	b, _ = newTestBar(io.Discard, 10, true)
+	b.Template = "{{.Bar}}"
+	b.Gradient = []*termdeco.Decorator{termdeco.Red(nil), termdeco.Green(nil)}
+	b.Set(10)
+	termdecotest.AssertStyled(t, b.String(), termdeco.Sprint(termdeco.Red("█████"))+termdeco.Sprint(termdeco.Green("█████")))
+}
+
+func TestBarIndeterminateAnimates(t *testing.T) {
+	var buf bytes.Buffer
+	b, c := newTestBar(&buf, 0, true)
+	b.Template = "{{.Bar}}"
+	b.RefreshInterval = 50 * time.Millisecond
+	b.Set(1)
+	for i := 0; i < 3; i++ {
+		c.advance(50 * time.Millisecond)
+		c.tick()
+	}
+	b.Finish()
+	var frames []string
+	for _, l := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\r")[1:] {
+		if len(frames) == 0 || frames[len(frames)-1] != l {
+			frames = append(frames, l)
+		}
+	}
+	expected := []string{"███░░░░░░░\x1b[K", "░███░░░░░░\x1b[K", "░░███░░░░░\x1b[K", "░░░███░░░░\x1b[K"}
+	if !reflect.DeepEqual(frames, expected) {
+		t.Errorf("frames = %q, expected %q", frames, expected)
+	}
+	if len(b.tmpls) != 1 {
+		t.Errorf("%d templates parsed, expected 1", len(b.tmpls))
+	}
+	select {
+	case c.ticks <- c.now():
+		t.Error("bar is still redrawn after Finish")
+	default:
+	}
+}
+
+func TestReaderWriter(t *testing.T) {
+	b, _ := newTestBar(io.Discard, 10, false)
+	var dst bytes.Buffer
+	if _, err := io.Copy(b.Writer(&dst), b.Reader(strings.NewReader("hello"))); err != nil {
+		t.Fatal(err)
+	}
+	if b.current != 10 || dst.String() != "hello" {
+		t.Errorf("current = %d, written %q, expected 10 and \"hello\"", b.current, dst.String())
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:       "0 B",
+		1023:    "1023 B",
+		1536:    "1.5 KiB",
+		5 << 20: "5.0 MiB",
+	}
+	for n, expected := range tests {
+		if s := FormatBytes(n); s != expected {
+			t.Errorf("FormatBytes(%d) = %q, expected %q", n, s, expected)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package termdeco
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Semantic prompt marks of OSC 133. Terminals supporting them can jump
+// between prompts and select the output of a command. A REPL prints them in
+// order for each command:
+//
+//	termdeco.Print(termdeco.Prompt("> "))
+//	line := readLine()
+//	termdeco.Print(termdeco.CommandExecuted)
+//	status := run(line)
+//	termdeco.Print(termdeco.CommandFinished(status))
+const (
+	// PromptStart marks the start of a prompt.
+	PromptStart = "\x1b]133;A\x1b\\"
+	// CommandStart marks the end of a prompt and the start of the command
+	// line typed by the user.
+	CommandStart = "\x1b]133;B\x1b\\"
+	// CommandExecuted marks the end of the command line and the start of
+	// its output.
+	CommandExecuted = "\x1b]133;C\x1b\\"
+)
+
+// Prompt returns prompt between PromptStart and CommandStart.
+func Prompt(prompt string) string {
+	return PromptStart + prompt + CommandStart
+}
+
+// CommandFinished returns a mark of the end of the output of a command which
+// exited with status. Terminals show a failed command by a mark in the
+// gutter.
+func CommandFinished(status int) string {
+	return "\x1b]133;D;" + strconv.Itoa(status) + "\x1b\\"
+}
+
+// Shell is a shell whose prompt is escaped by EscapePrompt.
+type Shell int
+
+const (
+	// ShellBash is bash, for PS1 and the other prompt variables.
+	ShellBash Shell = iota
+	// ShellZsh is zsh, for PROMPT and RPROMPT.
+	ShellZsh
+	// ShellFish is fish, for the output of fish_prompt.
+	ShellFish
+)
+
+// EscapePrompt returns the decorated string s for a prompt of shell. Runs of
+// escape sequences and control characters other than newline, carriage
+// return, tab and backspace are wrapped in \[ \] for bash and %{ %} for zsh,
+// so line editing counts only printed columns. fish needs no markers.
+//
+// Backslashes are doubled for bash and percent signs for zsh so they are
+// printed as is, including the one in ST of OSC sequences. Other expansions
+// like $ with promptvars and PROMPT_SUBST aren't escaped.
+func EscapePrompt(s string, shell Shell) string {
+	var open, close string
+	switch shell {
+	case ShellBash:
+		open, close = `\[`, `\]`
+	case ShellZsh:
+		open, close = "%{", "%}"
+	}
+	var b strings.Builder
+	inSeq := false
+	for s != "" {
+		t := NextToken(s)
+		s = s[len(t.Raw):]
+		seq := t.Kind != TextToken && !(t.Kind == ControlToken && strings.Contains("\n\r\t\b", t.Raw))
+		if seq != inSeq {
+			if seq {
+				b.WriteString(open)
+			} else {
+				b.WriteString(close)
+			}
+			inSeq = seq
+		}
+		switch shell {
+		case ShellBash:
+			b.WriteString(strings.ReplaceAll(t.Raw, `\`, `\\`))
+		case ShellZsh:
+			b.WriteString(strings.ReplaceAll(t.Raw, "%", "%%"))
+		default:
+			b.WriteString(t.Raw)
+		}
+	}
+	if inSeq {
+		b.WriteString(close)
+	}
+	return b.String()
+}
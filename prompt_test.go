@@ -0,0 +1,40 @@
+package termdeco
+
+import "testing"
+
+func TestPromptMarks(t *testing.T) {
+	tests := []struct {
+		got, expected string
+	}{
+		{Prompt("> "), "\x1b]133;A\x1b\\> \x1b]133;B\x1b\\"},
+		{CommandExecuted, "\x1b]133;C\x1b\\"},
+		{CommandFinished(0), "\x1b]133;D;0\x1b\\"},
+		{CommandFinished(127), "\x1b]133;D;127\x1b\\"},
+	}
+	for i, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("[%d] got %q, expected %q", i, tt.got, tt.expected)
+		}
+	}
+	if w := Width(Prompt(Sprint(Green("❯").Bold()) + " ")); w != 2 {
+		t.Errorf("Width of prompt = %d, expected 2", w)
+	}
+}
+
+func TestEscapePrompt(t *testing.T) {
+	setHyperlinks(t, true)
+	s := Sprint(Green("~/src").Bold()) + " 100% " + Sprint(Hyperlink("main", "u")) + "\n\a$ "
+	tests := []struct {
+		shell    Shell
+		expected string
+	}{
+		{ShellBash, "\\[\x1b[32;1m\\]~/src\\[\x1b[0m\\] 100% \\[\x1b]8;;u\x1b\\\\\\]main\\[\x1b]8;;\x1b\\\\\\]\n\\[\a\\]$ "},
+		{ShellZsh, "%{\x1b[32;1m%}~/src%{\x1b[0m%} 100%% %{\x1b]8;;u\x1b\\%}main%{\x1b]8;;\x1b\\%}\n%{\a%}$ "},
+		{ShellFish, s},
+	}
+	for _, tt := range tests {
+		if got := EscapePrompt(s, tt.shell); got != tt.expected {
+			t.Errorf("EscapePrompt(%q, %d) = %q, expected %q", s, tt.shell, got, tt.expected)
+		}
+	}
+}
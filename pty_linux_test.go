@@ -0,0 +1,78 @@
+// +build linux
+
+package termdeco
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+// openPty opens a pseudo-terminal pair via /dev/ptmx. It skips the test if
+// pseudo-terminals aren't available.
+func openPty(t *testing.T) (master, slave *os.File) {
+	t.Helper()
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("pseudo-terminal is not available: %v", err)
+	}
+	var unlock int32
+	if err := ioctl(master.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); err != nil {
+		master.Close()
+		t.Fatalf("failed to unlock pseudo-terminal: %v", err)
+	}
+	var n uint32
+	if err := ioctl(master.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); err != nil {
+		master.Close()
+		t.Fatalf("failed to get pseudo-terminal number: %v", err)
+	}
+	slave, err = os.OpenFile("/dev/pts/"+strconv.Itoa(int(n)), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		t.Skipf("pseudo-terminal is not available: %v", err)
+	}
+	t.Cleanup(func() {
+		slave.Close()
+		master.Close()
+	})
+	return master, slave
+}
+
+func setPtySize(t *testing.T, f *os.File, cols, rows int) {
+	t.Helper()
+	ws := winsize{rows: uint16(rows), cols: uint16(cols)}
+	if err := ioctl(f.Fd(), syscall.TIOCSWINSZ, uintptr(unsafe.Pointer(&ws))); err != nil {
+		t.Fatalf("failed to set pseudo-terminal size: %v", err)
+	}
+}
+
+func ioctl(fd, req, arg uintptr) error {
+	if _, _, e1 := syscall.Syscall(syscall.SYS_IOCTL, fd, req, arg); e1 != 0 {
+		return e1
+	}
+	return nil
+}
+
+// respond writes reply to master after reading a request which ends with
+// suffix from it, like a terminal. The request is sent to the returned
+// channel.
+func respond(master *os.File, suffix, reply string) <-chan string {
+	req := make(chan string, 1)
+	go func() {
+		var b []byte
+		p := make([]byte, 256)
+		for !bytes.HasSuffix(b, []byte(suffix)) {
+			n, err := master.Read(p)
+			if err != nil {
+				break
+			}
+			b = append(b, p[:n]...)
+		}
+		master.Write([]byte(reply))
+		req <- string(b)
+	}()
+	return req
+}
@@ -0,0 +1,226 @@
+package termdeco
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoReply is returned when the terminal doesn't reply to a query in time,
+// usually because it doesn't support the query.
+var ErrNoReply = errors.New("termdeco: no reply from the terminal")
+
+// request writes req to the terminal tty in raw mode and returns the first
+// token of its reply accepted by match. Other input read meanwhile, like
+// keys typed by the user, is discarded.
+func request(tty *os.File, req string, timeout time.Duration, match func(Token) bool) (Token, error) {
+	restore, err := makeRaw(tty)
+	if err != nil {
+		return Token{}, err
+	}
+	defer restore()
+	if _, err := io.WriteString(tty, req); err != nil {
+		return Token{}, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	var buf []byte
+	p := make([]byte, 256)
+	for {
+		n, err := tty.Read(p)
+		buf = append(buf, p[:n]...)
+		for len(buf) > 0 {
+			t := NextToken(string(buf))
+			if t.Incomplete && len(t.Raw) == len(buf) {
+				// the rest of the sequence hasn't arrived yet
+				break
+			}
+			buf = buf[len(t.Raw):]
+			if match(t) {
+				return t, nil
+			}
+		}
+		if err != nil && err != io.EOF {
+			return Token{}, err
+		}
+		if time.Now().After(deadline) {
+			return Token{}, ErrNoReply
+		}
+	}
+}
+
+// oscBody returns the text of an OSC token between ESC ] and its terminator.
+func oscBody(t Token) string {
+	s := t.Raw[len(oscStart):]
+	switch {
+	case len(s) > 0 && s[len(s)-1] == '\a':
+		return s[:len(s)-1]
+	case len(s) > 1 && s[len(s)-2:] == oscEnd:
+		return s[:len(s)-2]
+	}
+	return s
+}
+
+// queryDA1 is the primary device attributes request every terminal replies.
+const queryDA1 = "\x1b[c"
+
+func isDA1Reply(t Token) bool {
+	return t.Kind == CSIToken && t.Private() == '?' && t.Intermediate() == "" && t.Final() == 'c'
+}
+
+// query is request followed by queryDA1. As terminals reply in order, the
+// DA1 reply without a reply accepted by match means the query isn't
+// supported, and ErrNoReply is returned without waiting for the timeout.
+func query(tty *os.File, req string, timeout time.Duration, match func(Token) bool) (Token, error) {
+	var reply Token
+	found := false
+	_, err := request(tty, req+queryDA1, timeout, func(t Token) bool {
+		if !found && match(t) {
+			reply, found = t, true
+			return false
+		}
+		return isDA1Reply(t)
+	})
+	if found {
+		return reply, nil
+	}
+	if err == nil {
+		err = ErrNoReply
+	}
+	return Token{}, err
+}
+
+// QueryDeviceAttributes returns parameters of the primary device attributes
+// (DA1) of the terminal tty, the conformance level and supported features
+// like 4 for sixel graphics.
+func QueryDeviceAttributes(tty *os.File, timeout time.Duration) ([]int, error) {
+	t, err := request(tty, queryDA1, timeout, isDA1Reply)
+	if err != nil {
+		return nil, err
+	}
+	return t.Params(), nil
+}
+
+// QuerySecondaryDeviceAttributes returns parameters of the secondary device
+// attributes (DA2) of the terminal tty, its type, version and ROM cartridge
+// number.
+func QuerySecondaryDeviceAttributes(tty *os.File, timeout time.Duration) ([]int, error) {
+	t, err := query(tty, "\x1b[>c", timeout, func(t Token) bool {
+		return t.Kind == CSIToken && t.Private() == '>' && t.Final() == 'c'
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t.Params(), nil
+}
+
+// QueryVersion returns the name and version of the terminal tty like
+// "XTerm(388)" by XTVERSION.
+func QueryVersion(tty *os.File, timeout time.Duration) (string, error) {
+	const prefix = "\x1bP>|"
+	t, err := query(tty, "\x1b[>q", timeout, func(t Token) bool {
+		return t.Kind == StringToken && !t.Incomplete && strings.HasPrefix(t.Raw, prefix)
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(t.Raw[len(prefix):], oscEnd), nil
+}
+
+// QueryCursorPosition returns the cursor position of the terminal tty.
+// Rows and columns start at 1 like CursorPosition.
+func QueryCursorPosition(tty *os.File, timeout time.Duration) (row, col int, err error) {
+	t, err := query(tty, "\x1b[6n", timeout, func(t Token) bool {
+		return t.Kind == CSIToken && t.Private() == 0 && t.Final() == 'R' && len(t.Params()) == 2
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	p := t.Params()
+	return p[0], p[1], nil
+}
+
+// QueryForeground returns the default foreground color of the terminal tty.
+func QueryForeground(tty *os.File, timeout time.Duration) (color.RGBA, error) {
+	return queryColor(tty, "10", timeout)
+}
+
+// QueryBackground returns the default background color of the terminal tty.
+// It tells whether the terminal has a dark or light theme.
+func QueryBackground(tty *os.File, timeout time.Duration) (color.RGBA, error) {
+	return queryColor(tty, "11", timeout)
+}
+
+// QueryPalette returns the color of index in the palette of the terminal
+// tty.
+func QueryPalette(tty *os.File, index int, timeout time.Duration) (color.RGBA, error) {
+	return queryColor(tty, "4;"+strconv.Itoa(index), timeout)
+}
+
+// queryColor asks the color of OSC ps and parses a reply like
+// OSC ps ; rgb:ffff/ffff/ffff ST.
+func queryColor(tty *os.File, ps string, timeout time.Duration) (color.RGBA, error) {
+	prefix := oscStart + ps + ";"
+	t, err := query(tty, prefix+"?"+oscEnd, timeout, func(t Token) bool {
+		return t.Kind == OSCToken && !t.Incomplete && strings.HasPrefix(t.Raw, prefix)
+	})
+	if err != nil {
+		return color.RGBA{}, err
+	}
+	return parseXColor(strings.TrimPrefix(oscBody(t), ps+";"))
+}
+
+// parseXColor parses a color like rgb:ff/80/00 or rgb:ffff/8080/0000 in X11
+// format, whose components have 1 to 4 hex digits.
+func parseXColor(s string) (color.RGBA, error) {
+	parts := strings.Split(strings.TrimPrefix(s, "rgb:"), "/")
+	if !strings.HasPrefix(s, "rgb:") || len(parts) != 3 {
+		return color.RGBA{}, fmt.Errorf("termdeco: unknown color format %q", s)
+	}
+	var c [3]uint8
+	for i, p := range parts {
+		v, err := strconv.ParseUint(p, 16, 16)
+		if err != nil || len(p) == 0 || len(p) > 4 {
+			return color.RGBA{}, fmt.Errorf("termdeco: unknown color format %q", s)
+		}
+		max := uint64(1)<<(4*uint(len(p))) - 1
+		c[i] = uint8((v*255 + max/2) / max)
+	}
+	return color.RGBA{c[0], c[1], c[2], 0xff}, nil
+}
+
+// ModeState is the state of a mode reported by QueryMode.
+type ModeState int
+
+// States of DECRQM replies.
+const (
+	ModeNotRecognized ModeState = iota
+	ModeSet
+	ModeReset
+	ModePermanentlySet
+	ModePermanentlyReset
+)
+
+// Supported reports whether the mode can be set or reset.
+func (s ModeState) Supported() bool {
+	return s == ModeSet || s == ModeReset
+}
+
+// QueryMode returns the state of the DEC private mode of the terminal tty
+// by DECRQM, like 2026 for synchronized output.
+func QueryMode(tty *os.File, mode int, timeout time.Duration) (ModeState, error) {
+	t, err := query(tty, "\x1b[?"+strconv.Itoa(mode)+"$p", timeout, func(t Token) bool {
+		p := t.Params()
+		return t.Kind == CSIToken && t.Private() == '?' && t.Intermediate() == "$" && t.Final() == 'y' &&
+			len(p) == 2 && p[0] == mode
+	})
+	if err != nil {
+		return ModeNotRecognized, err
+	}
+	return ModeState(t.Params()[1]), nil
+}
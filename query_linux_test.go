@@ -0,0 +1,131 @@
+// +build linux
+
+package termdeco
+
+import (
+	"image/color"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  string
+		reply    string
+		query    func(tty *os.File) (interface{}, error)
+		expected interface{}
+	}{
+		{
+			"DA1", "\x1b[c", "\x1b[?62;4;22c",
+			func(tty *os.File) (interface{}, error) { return QueryDeviceAttributes(tty, time.Second) },
+			[]int{62, 4, 22},
+		},
+		{
+			"DA2", "\x1b[>c\x1b[c", "\x1b[>41;388;0c\x1b[?62c",
+			func(tty *os.File) (interface{}, error) { return QuerySecondaryDeviceAttributes(tty, time.Second) },
+			[]int{41, 388, 0},
+		},
+		{
+			"XTVERSION", "\x1b[>q\x1b[c", "\x1bP>|XTerm(388)\x1b\\\x1b[?62c",
+			func(tty *os.File) (interface{}, error) { return QueryVersion(tty, time.Second) },
+			"XTerm(388)",
+		},
+		{
+			"CPR", "\x1b[6n\x1b[c", "\x1b[12;40R\x1b[?62c",
+			func(tty *os.File) (interface{}, error) {
+				row, col, err := QueryCursorPosition(tty, time.Second)
+				return [2]int{row, col}, err
+			},
+			[2]int{12, 40},
+		},
+		{
+			"background", "\x1b]11;?\x1b\\\x1b[c", "\x1b]11;rgb:0000/8080/ffff\a\x1b[?62c",
+			func(tty *os.File) (interface{}, error) { return QueryBackground(tty, time.Second) },
+			color.RGBA{0x00, 0x80, 0xff, 0xff},
+		},
+		{
+			"foreground", "\x1b]10;?\x1b\\\x1b[c", "\x1b]10;rgb:e5/e5/e5\x1b\\\x1b[?62c",
+			func(tty *os.File) (interface{}, error) { return QueryForeground(tty, time.Second) },
+			color.RGBA{0xe5, 0xe5, 0xe5, 0xff},
+		},
+		{
+			"palette", "\x1b]4;1;?\x1b\\\x1b[c", "\x1b]4;1;rgb:c/0/0\x1b\\\x1b[?62c",
+			func(tty *os.File) (interface{}, error) { return QueryPalette(tty, 1, time.Second) },
+			color.RGBA{0xcc, 0x00, 0x00, 0xff},
+		},
+		{
+			"DECRQM", "\x1b[?2026$p\x1b[c", "\x1b[?2026;2$y\x1b[?62c",
+			func(tty *os.File) (interface{}, error) { return QueryMode(tty, 2026, time.Second) },
+			ModeReset,
+		},
+	}
+	for _, tt := range tests {
+		master, slave := openPty(t)
+		req := respond(master, "\x1b[c", tt.reply)
+		got, err := tt.query(slave)
+		if err != nil {
+			t.Errorf("%s: query failed: %v", tt.name, err)
+		} else if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: got %v, expected %v", tt.name, got, tt.expected)
+		}
+		if r := <-req; r != tt.request {
+			t.Errorf("%s: request = %q, expected %q", tt.name, r, tt.request)
+		}
+	}
+}
+
+func TestQueryUnsupported(t *testing.T) {
+	master, slave := openPty(t)
+	respond(master, "\x1b[c", "\x1b[?1;2c")
+	start := time.Now()
+	if _, err := QueryVersion(slave, 5*time.Second); err != ErrNoReply {
+		t.Errorf("err = %v, expected ErrNoReply", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("QueryVersion took %v, expected no wait for the timeout", d)
+	}
+}
+
+func TestQueryRestoresMode(t *testing.T) {
+	master, slave := openPty(t)
+	var before syscall.Termios
+	if err := getTermios(slave.Fd(), &before); err != nil {
+		t.Fatalf("getTermios failed: %v", err)
+	}
+	respond(master, "\x1b[c", "\x1b[?62c")
+	if _, err := QueryDeviceAttributes(slave, time.Second); err != nil {
+		t.Fatalf("QueryDeviceAttributes failed: %v", err)
+	}
+	var after syscall.Termios
+	if err := getTermios(slave.Fd(), &after); err != nil {
+		t.Fatalf("getTermios failed: %v", err)
+	}
+	if after != before || after.Lflag&syscall.ICANON == 0 {
+		t.Errorf("terminal mode isn't restored: %+v, expected %+v", after, before)
+	}
+}
+
+func TestParseXColor(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected color.RGBA
+		ok       bool
+	}{
+		{"rgb:ffff/0000/8080", color.RGBA{0xff, 0x00, 0x80, 0xff}, true},
+		{"rgb:f/8/0", color.RGBA{0xff, 0x88, 0x00, 0xff}, true},
+		{"rgb:fff/800/000", color.RGBA{0xff, 0x80, 0x00, 0xff}, true},
+		{"#ff0000", color.RGBA{}, false},
+		{"rgb:ff/gg/00", color.RGBA{}, false},
+		{"rgb:ff/00", color.RGBA{}, false},
+	}
+	for _, tt := range tests {
+		got, err := parseXColor(tt.s)
+		if (err == nil) != tt.ok || got != tt.expected {
+			t.Errorf("parseXColor(%q) = %v, %v", tt.s, got, err)
+		}
+	}
+}
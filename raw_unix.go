@@ -0,0 +1,46 @@
+// +build darwin freebsd linux netbsd openbsd
+
+package termdeco
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+func getTermios(fd uintptr, t *syscall.Termios) error {
+	if _, _, e1 := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlGetTermios, uintptr(unsafe.Pointer(t))); e1 != 0 {
+		return e1
+	}
+	return nil
+}
+
+func setTermios(fd uintptr, t *syscall.Termios) error {
+	if _, _, e1 := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlSetTermios, uintptr(unsafe.Pointer(t))); e1 != 0 {
+		return e1
+	}
+	return nil
+}
+
+// makeRaw puts the terminal f into raw mode until restore is called. Input
+// isn't echoed and is readable byte by byte, and a read returns nothing after
+// 100ms without input so readers can check their deadline. Output processing
+// is kept so newlines are written as usual.
+func makeRaw(f *os.File) (restore func() error, err error) {
+	fd := f.Fd()
+	var old syscall.Termios
+	if err := getTermios(fd, &old); err != nil {
+		return nil, err
+	}
+	raw := old
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 0
+	raw.Cc[syscall.VTIME] = 1
+	if err := setTermios(fd, &raw); err != nil {
+		return nil, err
+	}
+	return func() error { return setTermios(fd, &old) }, nil
+}
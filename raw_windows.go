@@ -0,0 +1,13 @@
+// +build windows
+
+package termdeco
+
+import (
+	"errors"
+	"os"
+)
+
+// makeRaw isn't supported as console input can't be read with a timeout.
+func makeRaw(f *os.File) (restore func() error, err error) {
+	return nil, errors.New("termdeco: reading replies from the console isn't supported on Windows")
+}
@@ -0,0 +1,91 @@
+package termdeco
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// SanitizeMode is how control characters and escape sequences in a decorated
+// value are neutralized. Newline, tab and carriage return just before a
+// newline are always kept.
+type SanitizeMode int
+
+const (
+	// SanitizeDefault uses DefaultSanitizeMode.
+	SanitizeDefault SanitizeMode = iota
+	// SanitizeEscape writes control characters as Go escapes like \x1b so
+	// escape sequences are printed as harmless text.
+	SanitizeEscape
+	// SanitizeReplace replaces each escape sequence and control character
+	// with U+FFFD.
+	SanitizeReplace
+	// SanitizeStrip removes escape sequences and control characters.
+	SanitizeStrip
+	// SanitizeNone prints a value as is. It must be used only for trusted
+	// values.
+	SanitizeNone
+)
+
+// DefaultSanitizeMode is the SanitizeMode of Decorators which don't set it.
+// Setting it to SanitizeNone restores the behavior of old versions, which
+// printed values as is, for programs passing pre-styled strings as values.
+var DefaultSanitizeMode = SanitizeEscape
+
+// Sanitize sets how control characters and escape sequences in the value are
+// neutralized.
+func (d *Decorator) Sanitize(mode SanitizeMode) *Decorator { d.sanitize = mode; return d }
+
+// Trusted prints the value as is including its escape sequences. It is for
+// intentionally pre-styled content like a string built with Sprint.
+func (d *Decorator) Trusted() *Decorator { d.sanitize = SanitizeNone; return d }
+
+// Sanitize returns s with its control characters and escape sequences
+// neutralized by mode.
+func Sanitize(s string, mode SanitizeMode) string {
+	if mode == SanitizeDefault {
+		mode = DefaultSanitizeMode
+	}
+	if mode == SanitizeNone || !needsSanitize(s) {
+		return s
+	}
+	var b strings.Builder
+	for s != "" {
+		t := NextToken(s)
+		s = s[len(t.Raw):]
+		if t.Kind == TextToken || t.Raw == "\n" || t.Raw == "\t" || (t.Raw == "\r" && strings.HasPrefix(s, "\n")) {
+			b.WriteString(t.Raw)
+			continue
+		}
+		switch mode {
+		case SanitizeReplace:
+			b.WriteRune(utf8.RuneError)
+		case SanitizeStrip:
+		default:
+			for _, r := range t.Raw {
+				if isControl(r) {
+					b.WriteString(escapeControl(r))
+				} else {
+					b.WriteRune(r)
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+func needsSanitize(s string) bool {
+	for i, r := range s {
+		if isControl(r) && r != '\n' && r != '\t' && !(r == '\r' && strings.HasPrefix(s[i+1:], "\n")) {
+			return true
+		}
+	}
+	return false
+}
+
+func escapeControl(r rune) string {
+	if r >= 0x80 {
+		return fmt.Sprintf(`\u%04x`, r)
+	}
+	return fmt.Sprintf(`\x%02x`, r)
+}
@@ -0,0 +1,90 @@
+package termdeco
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	s := "a\x1b]52;c;ZXZpbA==\x07b\tc\r\n\x1b[2Jd\u009be"
+	tests := []struct {
+		mode     SanitizeMode
+		expected string
+	}{
+		{SanitizeDefault, `a\x1b]52;c;ZXZpbA==\x07b` + "\tc\r\n" + `\x1b[2Jd\u009be`},
+		{SanitizeReplace, "a�b\tc\r\n�d�e"},
+		{SanitizeStrip, "ab\tc\r\nde"},
+		{SanitizeNone, s},
+	}
+	for _, tt := range tests {
+		if got := Sanitize(s, tt.mode); got != tt.expected {
+			t.Errorf("Sanitize(%q, %d) = %q, expected %q", s, tt.mode, got, tt.expected)
+		}
+	}
+}
+
+func TestDecoratorSanitize(t *testing.T) {
+	v := "evil\x1b]0;owned\x07"
+	tests := []struct {
+		d        *Decorator
+		expected string
+	}{
+		{Red(v), "\x1b[31mevil\\x1b]0;owned\\x07\x1b[0m"},
+		{Red(v).Sanitize(SanitizeStrip), "\x1b[31mevil\x1b[0m"},
+		{Red(v).Trusted(), "\x1b[31m" + v + "\x1b[0m"},
+		{Bold(Red(v)), "\x1b[1m\x1b[31mevil\\x1b]0;owned\\x07\x1b[0m\x1b[0m"},
+		{Bold(Sprint(Red("ok"))).Trusted(), "\x1b[1m\x1b[31mok\x1b[0m\x1b[0m"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(tt.d); got != tt.expected {
+			t.Errorf("Sprint = %q, expected %q", got, tt.expected)
+		}
+	}
+}
+
+func TestSanitizeCRLF(t *testing.T) {
+	tests := []struct {
+		s, expected string
+	}{
+		{"ab\r\ncd", "ab\r\ncd"},
+		{"ab\rcd", `ab\x0dcd`},
+		{"ab\r", `ab\x0d`},
+		{"ab\r\r\n", `ab\x0d` + "\r\n"},
+	}
+	for _, tt := range tests {
+		if got := Sanitize(tt.s, SanitizeEscape); got != tt.expected {
+			t.Errorf("Sanitize(%q) = %q, expected %q", tt.s, got, tt.expected)
+		}
+	}
+	decorated := []struct {
+		got, expected string
+	}{
+		{Sprint(BgBlue("ab\r\ncd").PerLine(false)), "\x1b[44mab\r\ncd\x1b[0m"},
+		{Sprint(BgBlue("ab\r\ncd")), "\x1b[44mab\x1b[0m\r\n\x1b[44mcd\x1b[0m"},
+		{Sprint(BgBlue("ab\r\n\r\ncd\n")), "\x1b[44mab\x1b[0m\r\n\r\n\x1b[44mcd\x1b[0m\n"},
+	}
+	for _, tt := range decorated {
+		if tt.got != tt.expected {
+			t.Errorf("Sprint = %q, expected %q", tt.got, tt.expected)
+		}
+	}
+}
+
+// TestSanitizePreStyledValue pins how a pre-styled string passed as a value
+// is printed, which changed when sanitizing became the default.
+func TestSanitizePreStyledValue(t *testing.T) {
+	styled := Sprint(Red("ok"))
+	if got, expected := Sprint(Bold(styled)), "\x1b[1m\\x1b[31mok\\x1b[0m\x1b[0m"; got != expected {
+		t.Errorf("default: got %q, expected %q", got, expected)
+	}
+	if got, expected := Sprint(Bold(styled).Trusted()), "\x1b[1m"+styled+"\x1b[0m"; got != expected {
+		t.Errorf("Trusted: got %q, expected %q", got, expected)
+	}
+
+	old := DefaultSanitizeMode
+	DefaultSanitizeMode = SanitizeNone
+	defer func() { DefaultSanitizeMode = old }()
+	if got, expected := Sprint(Bold(styled)), "\x1b[1m"+styled+"\x1b[0m"; got != expected {
+		t.Errorf("SanitizeNone: got %q, expected %q", got, expected)
+	}
+}
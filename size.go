@@ -0,0 +1,79 @@
+package termdeco
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// ErrUnknownSize is returned by Size if the size of the terminal can't be
+// detected.
+var ErrUnknownSize = errors.New("termdeco: unknown terminal size")
+
+// Resize is a size of a terminal delivered by NotifyResize.
+type Resize struct {
+	Cols, Rows int
+}
+
+// Size returns the number of columns and rows of the terminal w writes to.
+// If w isn't a terminal, like a pipe or a file, it falls back to COLUMNS and
+// LINES environment variables. It returns ErrUnknownSize if neither is
+// available.
+func Size(w io.Writer) (cols, rows int, err error) {
+	if cols, rows, err = termSize(w); err == nil && cols > 0 && rows > 0 {
+		return cols, rows, nil
+	}
+	cols, _ = strconv.Atoi(os.Getenv("COLUMNS"))
+	rows, _ = strconv.Atoi(os.Getenv("LINES"))
+	if cols <= 0 || rows <= 0 {
+		return 0, 0, ErrUnknownSize
+	}
+	return cols, rows, nil
+}
+
+// IsTerminal reports whether w writes to a terminal.
+func IsTerminal(w io.Writer) bool {
+	_, _, err := termSize(w)
+	return err == nil
+}
+
+// fder is a writer backed by a file descriptor like *os.File.
+type fder interface {
+	Fd() uintptr
+}
+
+// NotifyResize sends the new size of the terminal w writes to to c every
+// time it is resized, by SIGWINCH on Unix-like systems. Like signal.Notify,
+// it doesn't block sending to c, so c should be buffered. Calling the
+// returned function stops the notification.
+func NotifyResize(c chan<- Resize, w io.Writer) (stop func()) {
+	done := make(chan struct{})
+	events := make(chan struct{}, 1)
+	stopWatch := watchResize(events, w, done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-events:
+				cols, rows, err := Size(w)
+				if err != nil {
+					continue
+				}
+				select {
+				case c <- Resize{cols, rows}:
+				default:
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			stopWatch()
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// +build linux
+
+package termdeco
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSize(t *testing.T) {
+	master, slave := openPty(t)
+	setPtySize(t, master, 132, 43)
+	cols, rows, err := Size(slave)
+	if err != nil || cols != 132 || rows != 43 {
+		t.Errorf("Size = %d, %d, %v, expected 132, 43, nil", cols, rows, err)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	_, slave := openPty(t)
+	if !IsTerminal(slave) {
+		t.Error("IsTerminal(pty) = false, expected true")
+	}
+	if IsTerminal(&bytes.Buffer{}) {
+		t.Error("IsTerminal(buffer) = true, expected false")
+	}
+}
+
+func TestSizeFallback(t *testing.T) {
+	t.Setenv("COLUMNS", "100")
+	t.Setenv("LINES", "30")
+	cols, rows, err := Size(&bytes.Buffer{})
+	if err != nil || cols != 100 || rows != 30 {
+		t.Errorf("Size = %d, %d, %v, expected 100, 30, nil", cols, rows, err)
+	}
+
+	t.Setenv("COLUMNS", "")
+	if _, _, err := Size(&bytes.Buffer{}); err != ErrUnknownSize {
+		t.Errorf("Size error = %v, expected ErrUnknownSize", err)
+	}
+}
+
+func TestNotifyResize(t *testing.T) {
+	master, slave := openPty(t)
+	setPtySize(t, master, 80, 24)
+
+	c := make(chan Resize, 1)
+	stop := NotifyResize(c, slave)
+	defer stop()
+
+	setPtySize(t, master, 100, 40)
+	// the pseudo-terminal isn't our controlling terminal, so send SIGWINCH
+	// by ourselves
+	syscall.Kill(os.Getpid(), syscall.SIGWINCH)
+	select {
+	case r := <-c:
+		if r != (Resize{100, 40}) {
+			t.Errorf("Resize = %+v, expected {100 40}", r)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no resize notification")
+	}
+}
@@ -0,0 +1,49 @@
+// +build darwin freebsd linux netbsd openbsd
+
+package termdeco
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+type winsize struct {
+	rows, cols, xpixel, ypixel uint16
+}
+
+func termSize(w io.Writer) (cols, rows int, err error) {
+	f, ok := w.(fder)
+	if !ok {
+		return 0, 0, ErrUnknownSize
+	}
+	var ws winsize
+	_, _, e1 := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if e1 != 0 {
+		return 0, 0, e1
+	}
+	return int(ws.cols), int(ws.rows), nil
+}
+
+// watchResize notifies events on SIGWINCH until the returned function is
+// called.
+func watchResize(events chan<- struct{}, w io.Writer, done <-chan struct{}) (stop func()) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGWINCH)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-sigs:
+				select {
+				case events <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return func() { signal.Stop(sigs) }
+}
@@ -0,0 +1,53 @@
+// +build windows
+
+package termdeco
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// resizePollInterval is the interval to check the console size as Windows
+// has no signal for resizing.
+const resizePollInterval = 250 * time.Millisecond
+
+func termSize(w io.Writer) (cols, rows int, err error) {
+	f, ok := w.(*os.File)
+	if !ok {
+		return 0, 0, ErrUnknownSize
+	}
+	var info consoleScreenBufferInfo
+	if err := getConsoleScreenBufferInfo(f, &info); err != nil {
+		return 0, 0, err
+	}
+	cols = int(info.window.right-info.window.left) + 1
+	rows = int(info.window.bottom-info.window.top) + 1
+	return cols, rows, nil
+}
+
+// watchResize notifies events when the console size changes until the
+// returned function is called.
+func watchResize(events chan<- struct{}, w io.Writer, done <-chan struct{}) (stop func()) {
+	ticker := time.NewTicker(resizePollInterval)
+	go func() {
+		cols, rows, _ := termSize(w)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				c, r, err := termSize(w)
+				if err != nil || (c == cols && r == rows) {
+					continue
+				}
+				cols, rows = c, r
+				select {
+				case events <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return ticker.Stop
+}
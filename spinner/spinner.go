@@ -0,0 +1,216 @@
+// spinner is a package of spinners which show an operation is in progress.
+//
+//	s := spinner.New(os.Stderr, "fetching")
+//	s.Start()
+//	err := fetch()
+//	if err != nil {
+//		s.Failure(err)
+//	} else {
+//		s.Success("fetched")
+//	}
+//
+// A spinner is animated on its own goroutine on a terminal. Otherwise it
+// writes its messages as static lines.
+package spinner
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/tatsushid/termdeco"
+)
+
+// Frame sets of spinners.
+var (
+	Dots    = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	Line    = []string{"─", "\\", "│", "/"}
+	Braille = []string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}
+	// ASCII is for terminals which can't show the others.
+	ASCII = []string{"-", "\\", "|", "/"}
+)
+
+// defaultInterval is the interval of frames used if Interval isn't positive.
+const defaultInterval = 80 * time.Millisecond
+
+// Mark is a symbol which replaces the spinner at the end.
+type Mark struct {
+	Symbol string
+	Style  *termdeco.Decorator
+}
+
+// Spinner is a spinner with a message. Its methods are safe for concurrent
+// use.
+type Spinner struct {
+	Frames []string
+	// Interval is the interval of frames. 80ms is used if it is 0 or
+	// less.
+	Interval time.Duration
+	// Style is the style of frames and MessageStyle is the one of
+	// messages.
+	Style, MessageStyle *termdeco.Decorator
+	// SuccessMark, FailureMark and WarningMark are shown by Success,
+	// Failure and Warning.
+	SuccessMark, FailureMark, WarningMark Mark
+	// Interactive is whether the spinner is animated. New sets it if the
+	// output is a terminal.
+	Interactive bool
+
+	mu      sync.Mutex
+	w       io.Writer
+	msg     string
+	frame   int
+	running bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// New returns a Spinner writing to w with msg. msg is printed by
+// termdeco.Sprint.
+func New(w io.Writer, msg interface{}) *Spinner {
+	return &Spinner{
+		Frames:      Dots,
+		Interval:    defaultInterval,
+		Style:       termdeco.Cyan(nil),
+		SuccessMark: Mark{"✔", termdeco.Green(nil)},
+		FailureMark: Mark{"✖", termdeco.Red(nil)},
+		WarningMark: Mark{"⚠", termdeco.Yellow(nil)},
+		Interactive: termdeco.IsTerminal(w),
+		w:           w,
+		msg:         format(msg),
+	}
+}
+
+func format(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return termdeco.Sprint(v)
+}
+
+func apply(d *termdeco.Decorator, s string) string {
+	if d == nil || s == "" {
+		return s
+	}
+	return d.Apply(s)
+}
+
+// Start starts the spinner. It does nothing if the spinner is running.
+func (s *Spinner) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return
+	}
+	s.running = true
+	if !s.Interactive {
+		s.writeLine()
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	s.draw()
+	interval := s.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	go s.loop(interval, s.stop, s.done)
+}
+
+func (s *Spinner) loop(interval time.Duration, stop, done chan struct{}) {
+	defer close(done)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			s.mu.Lock()
+			s.frame++
+			s.draw()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// SetMessage replaces the message.
+func (s *Spinner) SetMessage(msg interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msg = format(msg)
+	if !s.running {
+		return
+	}
+	if s.Interactive {
+		s.draw()
+	} else {
+		s.writeLine()
+	}
+}
+
+// draw redraws the line on a terminal.
+func (s *Spinner) draw() {
+	frame := ""
+	if len(s.Frames) > 0 {
+		frame = apply(s.Style, s.Frames[s.frame%len(s.Frames)]) + " "
+	}
+	termdeco.Fprint(s.w, "\r"+frame+apply(s.MessageStyle, s.msg)+"\x1b[K")
+}
+
+// writeLine writes the message as a static line.
+func (s *Spinner) writeLine() {
+	termdeco.Fprint(s.w, apply(s.MessageStyle, s.msg)+"\n")
+}
+
+// halt stops the goroutine and reports whether the spinner was running.
+func (s *Spinner) halt() bool {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return false
+	}
+	s.running = false
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	return true
+}
+
+// Stop stops the spinner and clears its line.
+func (s *Spinner) Stop() {
+	if s.halt() && s.Interactive {
+		termdeco.Fprint(s.w, "\r\x1b[K")
+	}
+}
+
+// Success stops the spinner and replaces it with SuccessMark and msg. If msg
+// is nil, the current message is kept.
+func (s *Spinner) Success(msg interface{}) { s.finish(s.SuccessMark, msg) }
+
+// Failure stops the spinner and replaces it with FailureMark and msg. If msg
+// is nil, the current message is kept.
+func (s *Spinner) Failure(msg interface{}) { s.finish(s.FailureMark, msg) }
+
+// Warning stops the spinner and replaces it with WarningMark and msg. If msg
+// is nil, the current message is kept.
+func (s *Spinner) Warning(msg interface{}) { s.finish(s.WarningMark, msg) }
+
+func (s *Spinner) finish(m Mark, msg interface{}) {
+	s.halt()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if msg != nil {
+		s.msg = format(msg)
+	}
+	line := apply(m.Style, m.Symbol) + " " + apply(s.MessageStyle, s.msg)
+	if s.Interactive {
+		termdeco.Fprint(s.w, "\r"+line+"\x1b[K\n")
+	} else {
+		termdeco.Fprint(s.w, line+"\n")
+	}
+}
@@ -0,0 +1,107 @@
+package spinner
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tatsushid/termdeco"
+	"github.com/tatsushid/termdeco/termdecotest"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSpinnerInteractive(t *testing.T) {
+	var buf syncBuffer
+	s := New(&buf, "loading")
+	s.Interactive = true
+	s.Frames = ASCII
+	s.Style = nil
+	s.Interval = time.Hour
+	s.Start()
+	s.mu.Lock()
+	s.frame++
+	s.mu.Unlock()
+	s.SetMessage(termdeco.Bold("parsing"))
+	s.Success(nil)
+
+	expected := "\r- loading\x1b[K" +
+		"\r\\ " + termdeco.Sprint(termdeco.Bold("parsing")) + "\x1b[K" +
+		"\r" + termdeco.Sprint(termdeco.Green("✔")) + " " + termdeco.Sprint(termdeco.Bold("parsing")) + "\x1b[K\n"
+	if buf.String() != expected {
+		t.Errorf("output = %q, expected %q", buf.String(), expected)
+	}
+}
+
+func TestSpinnerStatic(t *testing.T) {
+	var buf bytes.Buffer
+	s := New(&buf, "step 1")
+	s.Interactive = false
+	s.Start()
+	s.SetMessage("step 2")
+	s.Failure(errors.New("failed"))
+	termdecotest.AssertStyled(t, buf.String(), "step 1\nstep 2\n"+termdeco.Sprint(termdeco.Red("✖"))+" failed\n")
+}
+
+func TestSpinnerConcurrent(t *testing.T) {
+	var buf syncBuffer
+	s := New(&buf, "working")
+	s.Interactive = true
+	s.Interval = time.Millisecond
+	s.Start()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				s.SetMessage("working")
+				time.Sleep(100 * time.Microsecond)
+			}
+		}()
+	}
+	wg.Wait()
+	s.Stop()
+	s.Stop()
+	out := buf.String()
+	if !strings.HasSuffix(out, "\r\x1b[K") {
+		t.Errorf("output = %q, expected to end with clearing the line", out)
+	}
+	n := len(out)
+	time.Sleep(10 * time.Millisecond)
+	if len(buf.String()) != n {
+		t.Error("spinner is still drawing after Stop")
+	}
+}
+
+func TestSpinnerZeroInterval(t *testing.T) {
+	var buf syncBuffer
+	s := New(&buf, "x")
+	s.Interactive = true
+	s.Interval = 0
+	s.Start()
+	time.Sleep(3 * defaultInterval)
+	s.Stop()
+	if n := strings.Count(buf.String(), "\r"); n < 3 {
+		t.Errorf("output = %q, expected frames drawn every %v", buf.String(), defaultInterval)
+	}
+}
@@ -0,0 +1,84 @@
+package table
+
+// Line is characters of a horizontal border line. A Line with empty Fill
+// isn't drawn.
+type Line struct {
+	Left, Fill, Cross, Right string
+}
+
+// Border is characters drawn around and between cells.
+type Border struct {
+	// Top is drawn above the table, Header between the header and the
+	// rows and Bottom below the table.
+	Top, Header, Bottom Line
+	// Left, Separator and Right are drawn on the left of the first column,
+	// between columns and on the right of the last column.
+	Left, Separator, Right string
+	// Padding is the number of spaces on both sides of each cell.
+	Padding int
+	// AlignMarks marks alignment of columns on the Header line with ':'
+	// like Markdown.
+	AlignMarks bool
+}
+
+var (
+	// BorderASCII draws borders with ASCII characters.
+	BorderASCII = Border{
+		Top:       Line{"+", "-", "+", "+"},
+		Header:    Line{"+", "-", "+", "+"},
+		Bottom:    Line{"+", "-", "+", "+"},
+		Left:      "|",
+		Separator: "|",
+		Right:     "|",
+		Padding:   1,
+	}
+
+	// BorderLight draws borders with light box drawing characters.
+	BorderLight = Border{
+		Top:       Line{"┌", "─", "┬", "┐"},
+		Header:    Line{"├", "─", "┼", "┤"},
+		Bottom:    Line{"└", "─", "┴", "┘"},
+		Left:      "│",
+		Separator: "│",
+		Right:     "│",
+		Padding:   1,
+	}
+
+	// BorderHeavy draws borders with heavy box drawing characters.
+	BorderHeavy = Border{
+		Top:       Line{"┏", "━", "┳", "┓"},
+		Header:    Line{"┣", "━", "╋", "┫"},
+		Bottom:    Line{"┗", "━", "┻", "┛"},
+		Left:      "┃",
+		Separator: "┃",
+		Right:     "┃",
+		Padding:   1,
+	}
+
+	// BorderRounded draws borders with light box drawing characters and
+	// rounded corners.
+	BorderRounded = Border{
+		Top:       Line{"╭", "─", "┬", "╮"},
+		Header:    Line{"├", "─", "┼", "┤"},
+		Bottom:    Line{"╰", "─", "┴", "╯"},
+		Left:      "│",
+		Separator: "│",
+		Right:     "│",
+		Padding:   1,
+	}
+
+	// BorderMarkdown draws a table in Markdown syntax.
+	BorderMarkdown = Border{
+		Header:     Line{"|", "-", "|", "|"},
+		Left:       "|",
+		Separator:  "|",
+		Right:      "|",
+		Padding:    1,
+		AlignMarks: true,
+	}
+
+	// BorderNone draws no border and separates columns with two spaces.
+	BorderNone = Border{
+		Separator: "  ",
+	}
+)
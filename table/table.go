@@ -0,0 +1,383 @@
+// table is a package to render tables of values decorated by termdeco.
+//
+// Columns are laid out by display width, so cells having escape sequences,
+// wide characters or emoji are aligned.
+//
+//	t := table.New("NAME", "STATUS")
+//	t.Append("web", termdeco.Green("running"))
+//	t.Append("db", termdeco.Red("stopped")).Style = termdeco.Bold(nil)
+//	t.Render(os.Stdout)
+//
+// A table is shrunk to fit the terminal width by truncating or wrapping
+// cells. Styles of a cell take precedence in order of Cell.Style,
+// Column.Style, Row.Style and Table.Stripe or Table.HeaderStyle.
+package table
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tatsushid/termdeco"
+)
+
+// Align is alignment of cells in a column.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// Ellipsis is appended to truncated cells.
+var Ellipsis = "…"
+
+// Column is settings of a column.
+type Column struct {
+	Align Align
+	// Style is applied to every cell in the column except the header.
+	Style *termdeco.Decorator
+	// MinWidth and MaxWidth limit the width of the column. MaxWidth 0
+	// means no limit.
+	MinWidth, MaxWidth int
+	// Wrap wraps cells wider than the column instead of truncating them.
+	Wrap bool
+}
+
+// Cell is a value with its own style. Style is applied to the whole width of
+// the cell while a Decorator value styles only its text.
+type Cell struct {
+	Value interface{}
+	Style *termdeco.Decorator
+}
+
+// Row is a row of a table. Cells are values or Cells. Decorators and the other
+// fmt.Formatters are printed by termdeco.Sprint and the other values are
+// sanitized by termdeco.Sanitize like values of Decorators, so styles are given
+// only by Decorators.
+type Row struct {
+	Cells []interface{}
+	Style *termdeco.Decorator
+}
+
+// Table is a table of rows and columns.
+type Table struct {
+	Header      []interface{}
+	HeaderStyle *termdeco.Decorator
+	Rows        []*Row
+	// Columns is settings of columns from the left. Missing columns have
+	// the default settings.
+	Columns     []Column
+	Border      Border
+	BorderStyle *termdeco.Decorator
+	// Stripe is applied to every other row for zebra striping.
+	Stripe *termdeco.Decorator
+	// MaxWidth is the maximum width of the whole table. If it is 0, Render
+	// uses the width of the terminal.
+	MaxWidth int
+}
+
+// New returns a Table with header, bold header style and BorderLight.
+func New(header ...interface{}) *Table {
+	return &Table{
+		Header:      header,
+		HeaderStyle: termdeco.Bold(nil),
+		Border:      BorderLight,
+	}
+}
+
+// Append adds a row of cells and returns it to set its style.
+func (t *Table) Append(cells ...interface{}) *Row {
+	r := &Row{Cells: cells}
+	t.Rows = append(t.Rows, r)
+	return r
+}
+
+// String returns the rendered table fit into MaxWidth if it is set.
+func (t *Table) String() string {
+	return t.render(t.MaxWidth)
+}
+
+// Render writes the table to w. If MaxWidth is 0, the table is fit into the
+// width of w's terminal.
+func (t *Table) Render(w io.Writer) error {
+	width := t.MaxWidth
+	if width == 0 {
+		if cols, _, err := termdeco.Size(w); err == nil {
+			width = cols
+		}
+	}
+	_, err := termdeco.Fprint(w, t.render(width))
+	return err
+}
+
+// cell is a formatted cell split into lines.
+type cell struct {
+	lines []string
+	style *termdeco.Decorator
+}
+
+func newCell(v interface{}) cell {
+	var style *termdeco.Decorator
+	switch c := v.(type) {
+	case Cell:
+		v, style = c.Value, c.Style
+	case *Cell:
+		v, style = c.Value, c.Style
+	}
+	return cell{lines: termdeco.Split(format(v)), style: style}
+}
+
+// format prints v for a cell. Only fmt.Formatters like Decorators can print
+// escape sequences.
+func format(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case fmt.Formatter:
+		return termdeco.Sprint(v)
+	}
+	return termdeco.Sanitize(fmt.Sprint(v), termdeco.SanitizeDefault)
+}
+
+func newCells(vs []interface{}, n int) []cell {
+	cells := make([]cell, n)
+	for i := range cells {
+		if i < len(vs) {
+			cells[i] = newCell(vs[i])
+		}
+	}
+	return cells
+}
+
+func (t *Table) column(i int) Column {
+	if i < len(t.Columns) {
+		return t.Columns[i]
+	}
+	return Column{}
+}
+
+func (t *Table) render(maxWidth int) string {
+	n := len(t.Header)
+	for _, r := range t.Rows {
+		if len(r.Cells) > n {
+			n = len(r.Cells)
+		}
+	}
+	if n == 0 {
+		return ""
+	}
+
+	var header []cell
+	if len(t.Header) > 0 {
+		header = newCells(t.Header, n)
+	}
+	body := make([][]cell, len(t.Rows))
+	for i, r := range t.Rows {
+		body[i] = newCells(r.Cells, n)
+	}
+
+	widths := make([]int, n)
+	for _, cells := range append([][]cell{header}, body...) {
+		for i, c := range cells {
+			for _, l := range c.lines {
+				if w := termdeco.Width(l); w > widths[i] {
+					widths[i] = w
+				}
+			}
+		}
+	}
+	for i := range widths {
+		col := t.column(i)
+		if col.MaxWidth > 0 && widths[i] > col.MaxWidth {
+			widths[i] = col.MaxWidth
+		}
+		if widths[i] < col.MinWidth {
+			widths[i] = col.MinWidth
+		}
+	}
+	// border lines are drawn with whole fill glyphs, which may be 2
+	// columns wide, so columns are widened to multiples of their width
+	step := t.fillWidth()
+	for i := range widths {
+		if r := (widths[i] + 2*t.Border.Padding) % step; r != 0 {
+			widths[i] += step - r
+		}
+	}
+	if maxWidth > 0 {
+		t.shrink(widths, maxWidth, step)
+	}
+
+	var b strings.Builder
+	t.writeLine(&b, t.Border.Top, widths, false)
+	if header != nil {
+		t.writeRow(&b, header, widths, true, nil, t.HeaderStyle)
+		t.writeLine(&b, t.Border.Header, widths, t.Border.AlignMarks)
+	}
+	for i, cells := range body {
+		var stripe *termdeco.Decorator
+		if i%2 == 1 {
+			stripe = t.Stripe
+		}
+		t.writeRow(&b, cells, widths, false, t.Rows[i].Style, stripe)
+	}
+	t.writeLine(&b, t.Border.Bottom, widths, false)
+	return b.String()
+}
+
+// fillWidth returns the width of the widest fill glyph of the border lines.
+func (t *Table) fillWidth() int {
+	w := 1
+	for _, l := range []Line{t.Border.Top, t.Border.Header, t.Border.Bottom} {
+		if fw := termdeco.Width(l.Fill); fw > w {
+			w = fw
+		}
+	}
+	return w
+}
+
+// shrink narrows the widest columns by step columns until the table fits
+// into maxWidth.
+func (t *Table) shrink(widths []int, maxWidth, step int) {
+	bd := t.Border
+	total := termdeco.Width(bd.Left) + termdeco.Width(bd.Right) + termdeco.Width(bd.Separator)*(len(widths)-1)
+	for _, w := range widths {
+		total += w + 2*bd.Padding
+	}
+	for ; total > maxWidth; total -= step {
+		widest := -1
+		for i, w := range widths {
+			min := t.column(i).MinWidth
+			if min < 1 {
+				min = 1
+			}
+			if w-step >= min && (widest < 0 || w > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			return
+		}
+		widths[widest] -= step
+	}
+}
+
+func (t *Table) writeLine(b *strings.Builder, l Line, widths []int, marks bool) {
+	if l.Fill == "" {
+		return
+	}
+	var s strings.Builder
+	s.WriteString(l.Left)
+	for i, w := range widths {
+		if i > 0 {
+			s.WriteString(l.Cross)
+		}
+		n := w + 2*t.Border.Padding
+		fill := repeatFill(l.Fill, n)
+		if marks && n >= 2 {
+			switch t.column(i).Align {
+			case AlignLeft:
+				fill = ":" + repeatFill(l.Fill, n-1)
+			case AlignRight:
+				fill = repeatFill(l.Fill, n-1) + ":"
+			case AlignCenter:
+				fill = ":" + repeatFill(l.Fill, n-2) + ":"
+			}
+		}
+		s.WriteString(fill)
+	}
+	s.WriteString(l.Right)
+	b.WriteString(apply(t.BorderStyle, s.String()))
+	b.WriteByte('\n')
+}
+
+// repeatFill repeats fill to occupy n columns. Box drawing characters are
+// East Asian Ambiguous, so fill may be 2 columns wide by
+// termdeco.AmbiguousWidth and the remainder is filled with spaces.
+func repeatFill(fill string, n int) string {
+	w := termdeco.Width(fill)
+	if w < 1 {
+		w = 1
+	}
+	return strings.Repeat(fill, n/w) + strings.Repeat(" ", n%w)
+}
+
+func (t *Table) writeRow(b *strings.Builder, cells []cell, widths []int, isHeader bool, rowStyle, outerStyle *termdeco.Decorator) {
+	lines := make([][]string, len(cells))
+	height := 1
+	for i, c := range cells {
+		lines[i] = fitCell(c.lines, widths[i], t.column(i).Wrap)
+		if len(lines[i]) > height {
+			height = len(lines[i])
+		}
+	}
+	pad := strings.Repeat(" ", t.Border.Padding)
+	last := len(cells) - 1
+	for l := 0; l < height; l++ {
+		var line strings.Builder
+		for i, c := range cells {
+			if i > 0 {
+				line.WriteString(apply(t.BorderStyle, t.Border.Separator))
+			}
+			col := t.column(i)
+			text := ""
+			if l < len(lines[i]) {
+				text = lines[i][l]
+			}
+			// don't leave trailing spaces without a right border and
+			// a row style filling them
+			openEnd := i == last && t.Border.Right == "" && col.Align == AlignLeft &&
+				rowStyle == nil && outerStyle == nil
+			if !openEnd {
+				text = align(text, widths[i], col.Align)
+			}
+			text = apply(c.style, text)
+			if !isHeader {
+				text = apply(col.Style, text)
+			}
+			if openEnd {
+				line.WriteString(pad + text)
+			} else {
+				line.WriteString(pad + text + pad)
+			}
+		}
+		b.WriteString(apply(t.BorderStyle, t.Border.Left))
+		b.WriteString(apply(outerStyle, apply(rowStyle, line.String())))
+		b.WriteString(apply(t.BorderStyle, t.Border.Right))
+		b.WriteByte('\n')
+	}
+}
+
+// fitCell truncates or wraps lines wider than width.
+func fitCell(lines []string, width int, wrap bool) []string {
+	out := make([]string, 0, len(lines))
+	for _, l := range lines {
+		switch {
+		case termdeco.Width(l) <= width:
+			out = append(out, l)
+		case wrap:
+			out = append(out, termdeco.Split(termdeco.Wrap(l, width, &termdeco.WrapOptions{BreakWords: true}))...)
+		default:
+			out = append(out, termdeco.Truncate(l, width, Ellipsis))
+		}
+	}
+	return out
+}
+
+func align(s string, width int, a Align) string {
+	switch a {
+	case AlignRight:
+		return termdeco.PadLeft(s, width)
+	case AlignCenter:
+		return termdeco.Center(s, width)
+	}
+	return termdeco.PadRight(s, width)
+}
+
+func apply(d *termdeco.Decorator, s string) string {
+	if d == nil {
+		return s
+	}
+	return d.Apply(s)
+}
@@ -0,0 +1,128 @@
+package table
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tatsushid/termdeco"
+	"github.com/tatsushid/termdeco/termdecotest"
+)
+
+func TestBorders(t *testing.T) {
+	tests := []struct {
+		border   Border
+		expected string
+	}{
+		{BorderASCII, "+------+-----+\n| name | qty |\n+------+-----+\n| 日本 |   3 |\n| abc  |  12 |\n+------+-----+\n"},
+		{BorderRounded, "╭──────┬─────╮\n│ name │ qty │\n├──────┼─────┤\n│ 日本 │   3 │\n│ abc  │  12 │\n╰──────┴─────╯\n"},
+		{BorderMarkdown, "| name | qty |\n|:-----|----:|\n| 日本 |   3 |\n| abc  |  12 |\n"},
+		{BorderNone, "name  qty\n日本    3\nabc    12\n"},
+	}
+	for _, tt := range tests {
+		tb := New("name", "qty")
+		tb.HeaderStyle = nil
+		tb.Border = tt.border
+		tb.Columns = []Column{{}, {Align: AlignRight}}
+		tb.Append("日本", 3)
+		tb.Append("abc", 12)
+		if s := tb.String(); s != tt.expected {
+			t.Errorf("table with %+v =\n%s\nexpected\n%s", tt.border, s, tt.expected)
+		}
+	}
+}
+
+func TestBordersAmbiguousWide(t *testing.T) {
+	defer func(w int) { termdeco.AmbiguousWidth = w }(termdeco.AmbiguousWidth)
+	termdeco.AmbiguousWidth = 2
+	tb := New("name", "qty")
+	tb.HeaderStyle = nil
+	tb.Append("abc", 12)
+	expected := "┌───┬───┐\n│ name │ qty  │\n├───┼───┤\n│ abc  │ 12   │\n└───┴───┘\n"
+	s := tb.String()
+	if s != expected {
+		t.Errorf("table =\n%s\nexpected\n%s", s, expected)
+	}
+	for _, l := range termdeco.Split(strings.TrimSuffix(s, "\n")) {
+		if w := termdeco.Width(l); w != 18 {
+			t.Errorf("Width(%q) = %d, expected 18", l, w)
+		}
+		if strings.Contains(l, "─ ") {
+			t.Errorf("border line %q has a gap", l)
+		}
+	}
+
+	// shrinking keeps columns in multiples of the fill width
+	tb.MaxWidth = 16
+	for _, l := range termdeco.Split(strings.TrimSuffix(tb.String(), "\n")) {
+		if w := termdeco.Width(l); w != 16 || strings.Contains(l, "─ ") {
+			t.Errorf("line %q of %d columns, expected 16 columns without a gap", l, w)
+		}
+	}
+}
+
+func TestSanitizedCells(t *testing.T) {
+	tb := New("name")
+	tb.HeaderStyle = nil
+	tb.Border = BorderNone
+	tb.Append("evil\x1b]0;pwned\x07")
+	tb.Append(termdeco.Red("ok"))
+	expected := "name\n" + `evil\x1b]0;pwned\x07` + "\n" + termdeco.Sprint(termdeco.Red("ok")) + "\n"
+	if s := tb.String(); s != expected {
+		t.Errorf("table = %q, expected %q", s, expected)
+	}
+}
+
+func TestStyledCells(t *testing.T) {
+	tb := New("name", "status")
+	tb.Border = BorderASCII
+	tb.Append(termdeco.Cyan("web"), termdeco.Green("ok"))
+	tb.Append("db", Cell{Value: "down", Style: termdeco.BgRed(nil)})
+	termdecotest.AssertClean(t, tb.String())
+	termdecotest.AssertStyled(t, tb.String(), ""+
+		"+------+--------+\n"+
+		"|"+termdeco.Sprint(termdeco.Bold(" name | status "))+"|\n"+
+		"+------+--------+\n"+
+		"| "+termdeco.Sprint(termdeco.Cyan("web"))+"  | "+termdeco.Sprint(termdeco.Green("ok"))+"     |\n"+
+		"| db   | "+termdeco.Sprint(termdeco.BgRed("down  "))+" |\n"+
+		"+------+--------+\n")
+}
+
+func TestStripeAndRowStyle(t *testing.T) {
+	tb := New()
+	tb.Border = BorderNone
+	tb.Stripe = termdeco.BgBrightBlack(nil)
+	tb.Append("a", termdeco.Red("1"))
+	tb.Append("b", termdeco.Red("2"))
+	tb.Append("c", "3").Style = termdeco.Bold(nil)
+	termdecotest.Golden(t, "stripe", tb.String())
+}
+
+func TestFitWidth(t *testing.T) {
+	tb := New("id", "description")
+	tb.Border = BorderASCII
+	tb.HeaderStyle = nil
+	tb.MaxWidth = 20
+	tb.Append(1, "a long description of the item")
+	expected := "" +
+		"+----+-------------+\n" +
+		"| id | description |\n" +
+		"+----+-------------+\n" +
+		"| 1  | a long des… |\n" +
+		"+----+-------------+\n"
+	if s := tb.String(); s != expected {
+		t.Errorf("truncated table =\n%s\nexpected\n%s", s, expected)
+	}
+
+	tb.Columns = []Column{{}, {Wrap: true}}
+	expected = "" +
+		"+----+-------------+\n" +
+		"| id | description |\n" +
+		"+----+-------------+\n" +
+		"| 1  | a long      |\n" +
+		"|    | description |\n" +
+		"|    | of the item |\n" +
+		"+----+-------------+\n"
+	if s := tb.String(); s != expected {
+		t.Errorf("wrapped table =\n%s\nexpected\n%s", s, expected)
+	}
+}
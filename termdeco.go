@@ -34,10 +34,18 @@
 //	termdeco.Red(v).Green()
 //
 // applies red and after that it overwrites so text printed as green
+//
+// Control characters and escape sequences in a decorated value are escaped
+// by default so an untrusted value like a file name can't change the terminal
+// state. Sanitize changes how they are neutralized and Trusted prints
+// intentionally pre-styled content as is. Older versions printed every value
+// as is; programs passing pre-styled strings as values should mark them with
+// Trusted, or set DefaultSanitizeMode to SanitizeNone.
 package termdeco
 
 import (
 	"fmt"
+	"strings"
 )
 
 const (
@@ -74,6 +82,9 @@ type Decorator struct {
 	Value               interface{}
 	fgClr, bgClr        int
 	isBold, isUnderline bool
+	sanitize            SanitizeMode
+	perLine             int
+	link, linkID        string
 }
 
 // It returns an empty Decorator.
@@ -155,6 +166,29 @@ func (d *Decorator) Bold() *Decorator       { d.isBold = true; return d }
 func (d *Decorator) Underline() *Decorator  { d.isUnderline = true; return d }
 func (d *Decorator) Underscore() *Decorator { d.isUnderline = true; return d }
 
+const (
+	c_PER_LINE_DEFAULT = iota
+	c_PER_LINE_ON
+	c_PER_LINE_OFF
+)
+
+// PerLine sets whether the style is closed before and opened again after
+// every newline in the value, so each line is styled on its own for pagers
+// and line-oriented writers. It is on by default only if a background color
+// is set, which otherwise fills the rest of the line on some terminals.
+func (d *Decorator) PerLine(on bool) *Decorator {
+	if on {
+		d.perLine = c_PER_LINE_ON
+	} else {
+		d.perLine = c_PER_LINE_OFF
+	}
+	return d
+}
+
+func (d *Decorator) isPerLine() bool {
+	return d.perLine == c_PER_LINE_ON || (d.perLine == c_PER_LINE_DEFAULT && d.bgClr > 0)
+}
+
 const (
 	keyEscape = 27
 )
@@ -214,19 +248,52 @@ var bgEscSeq = [][]byte{
 }
 
 // This is implementation of fmt.Formatter interface
+//
+// The formatted value is sanitized with the Decorator's SanitizeMode unless
+// the value is a Decorator, which sanitizes its own value. A width in the
+// verb like %-10s is the number of terminal columns measured by Width so wide
+// characters are aligned. See PerLine for values with newlines and Link for
+// hyperlinks.
 func (d *Decorator) Format(f fmt.State, c rune) {
-	format := ""
-	args := make([]interface{}, 0)
-	deco := d.buildEscSeq()
-	if len(deco) > 0 {
-		format += "%s"
-		args = append(args, deco)
+	deco := string(d.buildEscSeq())
+	value := d.formatValue(f, c)
+	reset := ""
+	if deco != "" {
+		reset = string(append(append(escSeq, escReset...), 'm'))
 	}
-	format += d.origFormat(f, c) + "%s"
-	reset := append(escSeq, escReset...)
-	reset = append(reset, 'm')
-	args = append(args, d.Value, reset)
-	fmt.Fprintf(f, format, args...)
+	plain := strings.TrimSpace(value)
+	// the url printed after the value without hyperlinks is also counted by
+	// the width, so the whole output is padded then
+	padLater := d.linkSuffix(plain) != ""
+	var out string
+	if deco != "" && d.isPerLine() && strings.Contains(value, "\n") {
+		// styles in the value are carried over lines by Split and
+		// CRs of CRLFs removed by it are put back
+		lines := Split(value)
+		raw := strings.Split(value, "\n")
+		for i, l := range lines {
+			if !padLater {
+				l = d.pad(f, l)
+			}
+			if l != "" {
+				l = deco + l + reset
+			}
+			if len(raw) == len(lines) && i < len(raw)-1 && strings.HasSuffix(raw[i], "\r") {
+				l += "\r"
+			}
+			lines[i] = l
+		}
+		out = strings.Join(lines, "\n")
+	} else if padLater {
+		out = deco + value + reset
+	} else {
+		out = deco + d.pad(f, value) + reset
+	}
+	out = d.withLink(out, plain)
+	if padLater {
+		out = d.pad(f, out)
+	}
+	fmt.Fprint(f, out)
 }
 
 func (d *Decorator) buildEscSeq() []byte {
@@ -259,14 +326,43 @@ func (d *Decorator) buildEscSeq() []byte {
 	return seq
 }
 
-func (d *Decorator) origFormat(f fmt.State, c rune) string {
+// formatValue formats and sanitizes the value. The width is left to pad
+// except for zero padding of numbers.
+func (d *Decorator) formatValue(f fmt.State, c rune) string {
+	zeroPad := f.Flag('0') && !f.Flag('-')
+	value := fmt.Sprintf(d.origFormat(f, c, zeroPad), d.Value)
+	if _, ok := d.Value.(*Decorator); !ok {
+		value = Sanitize(value, d.sanitize)
+	}
+	return value
+}
+
+// pad pads value to the width in the verb. fmt pads a value by the number of
+// runes, so the width is applied here by Width. Zero padding is already done
+// by formatValue.
+func (d *Decorator) pad(f fmt.State, value string) string {
+	w, hasWidth := f.Width()
+	zeroPad := f.Flag('0') && !f.Flag('-')
+	if hasWidth && !zeroPad {
+		if pad := w - Width(value); pad > 0 {
+			if f.Flag('-') {
+				value += strings.Repeat(" ", pad)
+			} else {
+				value = strings.Repeat(" ", pad) + value
+			}
+		}
+	}
+	return value
+}
+
+func (d *Decorator) origFormat(f fmt.State, c rune, withWidth bool) string {
 	format := "%"
 	for i := 0; i < 128; i++ {
 		if f.Flag(i) {
-			format += string(i)
+			format += string(rune(i))
 		}
 	}
-	if w, ok := f.Width(); ok {
+	if w, ok := f.Width(); ok && withWidth {
 		format += fmt.Sprintf("%d", w)
 	}
 	if p, ok := f.Precision(); ok {
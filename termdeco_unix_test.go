@@ -1,15 +1,20 @@
 // +build darwin freebsd linux netbsd openbsd
 
-package termdeco
+package termdeco_test
 
 import (
-	"fmt"
+	"bytes"
 	"testing"
+
+	"github.com/tatsushid/termdeco"
+	"github.com/tatsushid/termdeco/termdecotest"
 )
 
 func TestDecoration(t *testing.T) {
-	Printf("Test %s = Bright Red Fg, Green Bg\n", BrightRed("test").BgGreen())
-	Println("Test", BrightWhite("bold").Bold().Underline().BgBlack(), "= Bright White Fg, Black Bg, Bold, Underline")
-	s := Sprintf("Test %d = Blue Fg, Yellow Bg", Blue(1234).BgYellow())
-	fmt.Println(s)
+	var buf bytes.Buffer
+	termdeco.Fprintf(&buf, "Test %s = Bright Red Fg, Green Bg\n", termdeco.BrightRed("test").BgGreen())
+	termdeco.Fprintln(&buf, "Test", termdeco.BrightWhite("bold").Bold().Underline().BgBlack(), "= Bright White Fg, Black Bg, Bold, Underline")
+	buf.WriteString(termdeco.Sprintf("Test %d = Blue Fg, Yellow Bg", termdeco.Blue(1234).BgYellow()))
+	termdecotest.AssertClean(t, buf.String())
+	termdecotest.Golden(t, "decoration", buf.String())
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"syscall"
 	"unsafe"
 )
@@ -198,56 +199,63 @@ func printEscString(f *os.File, str string) (n int, err error) {
 		defaultAttr = stderrDefaultAttr
 	}
 
-	runes := []rune(str)
-	end := len(runes)
 	printStr := ""
-	for i := 0; i < end; {
-		for i < end && runes[i] != keyEscape {
-			printStr += string(runes[i])
-			i++
-		}
-		if i >= end {
-			wn, err := fmt.Fprint(f, printStr)
-			n += wn
-			if err != nil {
-				return n, err
+	// link is the URI of the hyperlink open and linkText is its text
+	var link, linkText string
+	for str != "" {
+		t := NextToken(str)
+		str = str[len(t.Raw):]
+		if t.Kind == TextToken || t.Kind == ControlToken {
+			printStr += t.Raw
+			if link != "" {
+				linkText += t.Raw
 			}
-			break
-		}
-		if runes[i+1] != '[' {
-			printStr += string(runes[i])
-			i++
 			continue
 		}
-		i += 2
 		wn, err := fmt.Fprint(f, printStr)
 		n += wn
 		if err != nil {
 			return n, err
 		}
 		printStr = ""
+		if t.Incomplete {
+			break
+		}
 
-		seq := make([]byte, 0)
-		attr := word(0)
-		for i < end && runes[i] != 'm' {
-			if runes[i] == ';' {
-				attr = addAttrOfSeq(attr, defaultAttr, seq)
-				seq = make([]byte, 0)
-			} else {
-				seq = append(seq, byte(runes[i]))
+		if isLinkToken(t) {
+			// the console can't show hyperlinks, so the URI is printed
+			// after the text like Link does without HyperlinksEnabled
+			if link != "" && strings.TrimSpace(linkText) != link {
+				wn, err := fmt.Fprint(f, " ("+link+")")
+				n += wn
+				if err != nil {
+					return n, err
+				}
 			}
-			i++
-		}
-		if i >= end {
-			break
+			link, linkText = linkURI(t), ""
+			continue
 		}
 
-		attr = addAttrOfSeq(attr, defaultAttr, seq)
-		err = setConsoleTextAttribute(f, attr)
+		if t.Kind == CSIToken && t.Final() == 'm' && t.Private() == 0 && t.Intermediate() == "" {
+			params, _ := t.csiBody()
+			attr := word(0)
+			for _, seq := range strings.Split(params, ";") {
+				attr = addAttrOfSeq(attr, defaultAttr, []byte(seq))
+			}
+			err = setConsoleTextAttribute(f, attr)
+		} else {
+			// cursor and screen controls are translated and the others
+			// are dropped rather than printed as garbage
+			err = consoleControl(f, t)
+		}
 		if err != nil {
 			return n, err
 		}
-		i++
+	}
+	wn, err := fmt.Fprint(f, printStr)
+	n += wn
+	if err != nil {
+		return n, err
 	}
 	err = setConsoleTextAttribute(f, defaultAttr)
 	if err != nil {
@@ -0,0 +1,199 @@
+// termdecotest is a package of helpers for testing decorated output.
+//
+// AssertStyled compares two strings by their visible text and the style of
+// each character, so output is checked by what a user sees regardless of how
+// escape sequences are arranged.
+//
+//	termdecotest.AssertStyled(t, got, termdeco.Sprint(termdeco.Red("error")))
+//
+// Golden compares output with a golden file in testdata directory and the
+// golden files are updated by running tests with -update flag.
+//
+// Failures are reported with markup like "[fg:red bold]error[/]: not found"
+// which shows styles of text in a readable form.
+package termdecotest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/tatsushid/termdeco"
+	"github.com/tatsushid/termdeco/lint"
+	"github.com/tatsushid/termdeco/vt"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// updating reports whether golden files should be updated.
+func updating() bool {
+	return *update
+}
+
+// styledRune is a visible character and its style.
+type styledRune struct {
+	r     rune
+	style vt.Style
+}
+
+// parse interprets SGR sequences in s and returns its characters with their
+// styles. The other escape sequences are ignored and control characters have
+// the default style.
+func parse(s string) []styledRune {
+	var rs []styledRune
+	var style vt.Style
+	for _, t := range termdeco.Tokenize(s) {
+		switch t.Kind {
+		case termdeco.TextToken:
+			for _, r := range t.Raw {
+				rs = append(rs, styledRune{r, style})
+			}
+		case termdeco.ControlToken:
+			r, _ := utf8.DecodeRuneInString(t.Raw)
+			rs = append(rs, styledRune{r: r})
+		case termdeco.CSIToken:
+			if t.IsSGR() {
+				style = style.ApplySGR(t.Params())
+			}
+		}
+	}
+	return rs
+}
+
+// Markup returns s with its escape sequences replaced by readable style
+// annotations like "[fg:red bold]error[/]". A '[' in the text is written as
+// "[[".
+func Markup(s string) string {
+	return markup(parse(s))
+}
+
+func markup(rs []styledRune) string {
+	var b strings.Builder
+	var cur vt.Style
+	for _, sr := range rs {
+		if sr.style != cur {
+			if cur != (vt.Style{}) {
+				b.WriteString("[/]")
+			}
+			if sr.style != (vt.Style{}) {
+				fmt.Fprintf(&b, "[%s]", sr.style)
+			}
+			cur = sr.style
+		}
+		if sr.r == '[' {
+			b.WriteString("[[")
+		} else {
+			b.WriteRune(sr.r)
+		}
+	}
+	if cur != (vt.Style{}) {
+		b.WriteString("[/]")
+	}
+	return b.String()
+}
+
+func text(rs []styledRune) string {
+	b := make([]rune, len(rs))
+	for i, sr := range rs {
+		b[i] = sr.r
+	}
+	return string(b)
+}
+
+// StyledDiff compares got and want by their visible text and styles and
+// returns a readable description of the differences. It returns "" if they
+// look the same on a terminal.
+func StyledDiff(got, want string) string {
+	g, w := parse(got), parse(want)
+	if text(g) != text(w) {
+		return fmt.Sprintf("text differs:\n got: %q\nwant: %q\n\n got: %s\nwant: %s",
+			text(g), text(w), markup(g), markup(w))
+	}
+
+	var b strings.Builder
+	line, col := 1, 1
+	for i := 0; i < len(g); {
+		if g[i].style == w[i].style {
+			if g[i].r == '\n' {
+				line, col = line+1, 1
+			} else {
+				col++
+			}
+			i++
+			continue
+		}
+		// group characters having the same mismatch in a line
+		j := i
+		for j < len(g) && g[j].r != '\n' && g[j].style == g[i].style && w[j].style == w[i].style {
+			j++
+		}
+		if j == i {
+			j++
+		}
+		fmt.Fprintf(&b, "line %d, column %d-%d %q: got [%s], want [%s]\n",
+			line, col, col+j-i-1, text(g[i:j]), g[i].style, w[i].style)
+		col += j - i
+		i = j
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return fmt.Sprintf("styles differ:\n%s\n got: %s\nwant: %s", b.String(), markup(g), markup(w))
+}
+
+// AssertStyled reports an error if got and want don't look the same on a
+// terminal.
+func AssertStyled(t testing.TB, got, want string) {
+	t.Helper()
+	if diff := StyledDiff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+// GoldenPath returns the path of the golden file for name,
+// testdata/<name>.golden.
+func GoldenPath(name string) string {
+	return filepath.Join("testdata", name+".golden")
+}
+
+// Golden compares got with the content of the golden file for name. If tests
+// run with -update flag, the golden file is written with got instead.
+func Golden(t testing.TB, name string, got string) {
+	t.Helper()
+	path := GoldenPath(name)
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create golden file directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	want := string(b)
+	if got == want {
+		return
+	}
+	if diff := StyledDiff(got, want); diff != "" {
+		t.Errorf("output differs from %s:\n%s", path, diff)
+		return
+	}
+	t.Errorf("output differs from %s in escape sequences only:\n got: %q\nwant: %q", path, got, want)
+}
+
+// AssertClean reports an error for each problem of escape sequences in s
+// found by lint.Check.
+func AssertClean(t testing.TB, s string) {
+	t.Helper()
+	for _, p := range lint.Check(s) {
+		t.Errorf("%s (%s) in %q", p, p.Kind, s)
+	}
+}
@@ -0,0 +1,55 @@
+package termdecotest
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/tatsushid/termdeco"
+)
+
+func TestMarkup(t *testing.T) {
+	s := termdeco.Sprint(termdeco.Red("error").Bold(), " [x] ", termdeco.BgBrightBlue("\x1b[38;5;200mok").Trusted())
+	expected := "[fg:red bold]error[/] [[x] [fg:color(200) bg:bright-blue]ok[/]"
+	if m := Markup(s); m != expected {
+		t.Errorf("Markup = %q, expected %q", m, expected)
+	}
+}
+
+func TestStyledDiff(t *testing.T) {
+	// the same look with different escape sequences
+	if d := StyledDiff("\x1b[1m\x1b[31mab\x1b[0m", "\x1b[31;1ma\x1b[1mb\x1b[m"); d != "" {
+		t.Errorf("StyledDiff should be empty, got:\n%s", d)
+	}
+
+	d := StyledDiff("x\n\x1b[31merror\x1b[0m!", "x\n\x1b[31;1merror\x1b[0m!")
+	if !strings.Contains(d, `line 2, column 1-5 "error": got [fg:red], want [fg:red bold]`) {
+		t.Errorf("unexpected StyledDiff:\n%s", d)
+	}
+
+	d = StyledDiff("\x1b[31merror\x1b[0m", "\x1b[31mfailure\x1b[0m")
+	if !strings.HasPrefix(d, "text differs:") {
+		t.Errorf("unexpected StyledDiff:\n%s", d)
+	}
+}
+
+func TestAssertClean(t *testing.T) {
+	AssertClean(t, termdeco.Sprintln(termdeco.Red("a"), termdeco.Bold("b").Underline()))
+}
+
+func TestGolden(t *testing.T) {
+	Golden(t, "decoration", termdeco.Sprintf("%s %5d\n", termdeco.Green("ok").Underline(), termdeco.Blue(42)))
+}
+
+func TestUpdating(t *testing.T) {
+	if updating() {
+		t.Skip("golden files are being updated")
+	}
+	if err := flag.Set("update", "true"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { flag.Set("update", "false") })
+	if !updating() {
+		t.Error("updating() = false with -update")
+	}
+}
@@ -0,0 +1,10 @@
+// +build darwin freebsd netbsd openbsd
+
+package termdeco
+
+import "syscall"
+
+const (
+	ioctlGetTermios = syscall.TIOCGETA
+	ioctlSetTermios = syscall.TIOCSETA
+)
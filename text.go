@@ -0,0 +1,241 @@
+package termdeco
+
+import (
+	"strings"
+)
+
+const resetSeq = "\x1b[0m"
+
+// sgrState is SGR sequences in effect since the last reset and the OSC 8
+// sequence of the hyperlink open at the point.
+type sgrState struct {
+	sgr  []string
+	link string
+}
+
+func (st *sgrState) apply(t Token) {
+	if isLinkToken(t) {
+		st.link = ""
+		if !isLinkClose(t) {
+			st.link = t.Raw
+		}
+		return
+	}
+	if p := t.Params(); len(p) == 0 || p[0] == 0 {
+		st.sgr = st.sgr[:0]
+		if len(p) <= 1 {
+			return
+		}
+	}
+	st.sgr = append(st.sgr, t.Raw)
+}
+
+// open returns escape sequences to start the style and the link in effect
+// again.
+func (st sgrState) open() string { return st.link + strings.Join(st.sgr, "") }
+
+// close returns escape sequences to end the style and the link in effect if
+// any.
+func (st sgrState) close() string {
+	s := ""
+	if len(st.sgr) > 0 {
+		s = resetSeq
+	}
+	if st.link != "" {
+		s += linkClose
+	}
+	return s
+}
+
+// isState reports whether t changes sgrState.
+func isState(t Token) bool {
+	return (t.Kind == CSIToken && t.IsSGR()) || isLinkToken(t)
+}
+
+// Apply returns s decorated with d's style regardless of d's Value. Escape
+// sequences in s are kept as is and d's style is started again after every
+// reset in s, so d's style covers the whole of s and styles in s take
+// precedence. Newlines are handled as Format does by PerLine and a link set
+// by Link covers the whole of s.
+func (d *Decorator) Apply(s string) string {
+	deco := string(d.buildEscSeq())
+	if s == "" {
+		return s
+	}
+	plain := Sanitize(s, SanitizeStrip)
+	if deco == "" {
+		return d.withLink(s, plain)
+	}
+	perLine := d.isPerLine()
+	var b strings.Builder
+	b.WriteString(deco)
+	for s != "" {
+		t := NextToken(s)
+		s = s[len(t.Raw):]
+		switch {
+		case t.Raw == "\n" && perLine:
+			b.WriteString(resetSeq + "\n")
+			if s != "" {
+				b.WriteString(deco)
+			}
+			continue
+		case t.Kind == CSIToken && t.IsSGR():
+			b.WriteString(t.Raw)
+			if p := t.Params(); s != "" && (len(p) == 0 || (len(p) == 1 && p[0] == 0)) {
+				b.WriteString(deco)
+			}
+			continue
+		}
+		b.WriteString(t.Raw)
+	}
+	if !perLine || !strings.HasSuffix(b.String(), "\n") {
+		b.WriteString(resetSeq)
+	}
+	return d.withLink(b.String(), plain)
+}
+
+// slice returns the part of s from column from to column to without closing
+// its style, and the style in effect at its end. A wide character cut at
+// from is replaced by spaces and one cut at to is replaced by spaces only if
+// pad is true.
+func slice(s string, from, to int, pad bool) (string, sgrState) {
+	var b strings.Builder
+	var st sgrState
+	started := false
+	start := func() {
+		if !started {
+			started = true
+			b.WriteString(st.open())
+		}
+	}
+	col := 0
+	for s != "" && col < to {
+		t := NextToken(s)
+		s = s[len(t.Raw):]
+		switch {
+		case isState(t):
+			st.apply(t)
+			if started {
+				b.WriteString(t.Raw)
+			}
+		case t.Kind == TextToken:
+			for text := t.Raw; text != "" && col < to; {
+				g, w := NextGrapheme(text)
+				text = text[len(g):]
+				switch {
+				case col < from && col+w <= from:
+				case col < from:
+					start()
+					end := col + w
+					if end > to {
+						end = to
+					}
+					b.WriteString(strings.Repeat(" ", end-from))
+				case col+w > to:
+					if pad {
+						start()
+						b.WriteString(strings.Repeat(" ", to-col))
+					}
+				default:
+					start()
+					b.WriteString(g)
+				}
+				col += w
+			}
+		case col >= from:
+			start()
+			b.WriteString(t.Raw)
+		}
+	}
+	if !started {
+		return "", sgrState{}
+	}
+	return b.String(), st
+}
+
+// Slice returns the part of a single line s from column from to column to,
+// counted by Width. Styles and hyperlinks in effect at from are started again
+// and ones in effect at to are closed. A wide character cut by from or to is replaced by
+// spaces.
+func Slice(s string, from, to int) string {
+	if from < 0 {
+		from = 0
+	}
+	body, st := slice(s, from, to, true)
+	return body + st.close()
+}
+
+// Truncate shortens a single line s to width columns by cutting its end and
+// appending tail like "…" if it is wider than width. Styles and hyperlinks in
+// effect at the cut are closed before tail.
+func Truncate(s string, width int, tail string) string {
+	if width <= 0 {
+		return ""
+	}
+	if Width(s) <= width {
+		return s
+	}
+	tw := Width(tail)
+	if tw > width {
+		return Truncate(tail, width, "")
+	}
+	body, st := slice(s, 0, width-tw, false)
+	return body + st.close() + tail
+}
+
+// PadLeft returns s with spaces added to its left so it occupies width
+// columns.
+func PadLeft(s string, width int) string {
+	if n := width - Width(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+// PadRight returns s with spaces added to its right so it occupies width
+// columns.
+func PadRight(s string, width int) string {
+	if n := width - Width(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// Center returns s with spaces added to both sides so it occupies width
+// columns. If the spaces can't be split evenly, the right side gets one more.
+func Center(s string, width int) string {
+	n := width - Width(s)
+	if n <= 0 {
+		return s
+	}
+	return strings.Repeat(" ", n/2) + s + strings.Repeat(" ", n-n/2)
+}
+
+// Split splits s into lines. Each line is closed with a reset if a style is
+// in effect at its end and the style is started again on the next line, so
+// every line can be printed on its own. Hyperlinks are closed and opened
+// again in the same way. A "\r\n" is also a line break.
+func Split(s string) []string {
+	var lines []string
+	var b strings.Builder
+	var st sgrState
+	for s != "" {
+		t := NextToken(s)
+		s = s[len(t.Raw):]
+		switch {
+		case t.Raw == "\r" && strings.HasPrefix(s, "\n"):
+		case t.Raw == "\n":
+			b.WriteString(st.close())
+			lines = append(lines, b.String())
+			b.Reset()
+			b.WriteString(st.open())
+		case isState(t):
+			st.apply(t)
+			b.WriteString(t.Raw)
+		default:
+			b.WriteString(t.Raw)
+		}
+	}
+	b.WriteString(st.close())
+	return append(lines, b.String())
+}
@@ -0,0 +1,91 @@
+package termdeco
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSlice(t *testing.T) {
+	s := "ab\x1b[31mcd\x1b[1mef\x1b[0mgh"
+	tests := []struct {
+		from, to int
+		expected string
+	}{
+		{0, 2, "ab"},
+		{1, 3, "b\x1b[31mc\x1b[0m"},
+		{3, 5, "\x1b[31md\x1b[1me\x1b[0m"},
+		{5, 7, "\x1b[31m\x1b[1mf\x1b[0mg"},
+		{6, 10, "gh"},
+		{8, 10, ""},
+		{1, 4, "b\x1b[31mcd\x1b[0m"},
+	}
+	for _, tt := range tests {
+		if got := Slice(s, tt.from, tt.to); got != tt.expected {
+			t.Errorf("Slice(%q, %d, %d) = %q, expected %q", s, tt.from, tt.to, got, tt.expected)
+		}
+	}
+
+	if got := Slice("日本語", 1, 5); got != " 本 " {
+		t.Errorf("Slice cutting wide characters = %q, expected %q", got, " 本 ")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s        string
+		width    int
+		expected string
+	}{
+		{"short", 10, "short"},
+		{"\x1b[32mpassed\x1b[0m", 6, "\x1b[32mpassed\x1b[0m"},
+		{"\x1b[32mpassed\x1b[0m tests", 5, "\x1b[32mpass\x1b[0m…"},
+		{"日本語テキスト", 6, "日本…"},
+		{"日本語テキスト", 5, "日本…"},
+		{"abc", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.s, tt.width, "…"); got != tt.expected {
+			t.Errorf("Truncate(%q, %d) = %q, expected %q", tt.s, tt.width, got, tt.expected)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	s := Sprint(Red("日本"))
+	if got := PadRight(s, 6); got != s+"  " {
+		t.Errorf("PadRight = %q", got)
+	}
+	if got := PadLeft(s, 5); got != " "+s {
+		t.Errorf("PadLeft = %q", got)
+	}
+	if got := Center("ab", 5); got != " ab  " {
+		t.Errorf("Center = %q", got)
+	}
+	if got := PadRight("toolong", 3); got != "toolong" {
+		t.Errorf("PadRight = %q, expected unchanged", got)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	got := Split("a\x1b[31mb\r\nc\nd\x1b[0me\n")
+	expected := []string{"a\x1b[31mb\x1b[0m", "\x1b[31mc\x1b[0m", "\x1b[31md\x1b[0me", ""}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Split = %q, expected %q", got, expected)
+	}
+}
+
+func TestDecoratorApply(t *testing.T) {
+	tests := []struct {
+		got, expected string
+	}{
+		{NewDecorator().Apply("plain"), "plain"},
+		{Bold(nil).Apply("a\x1b[31mb\x1b[0mc"), "\x1b[1ma\x1b[31mb\x1b[0m\x1b[1mc\x1b[0m"},
+		{BgBlue(nil).Apply("a\nb\n"), "\x1b[44ma\x1b[0m\n\x1b[44mb\x1b[0m\n"},
+		{Red(nil).Apply("a\nb"), "\x1b[31ma\nb\x1b[0m"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("Apply = %q, expected %q", tt.got, tt.expected)
+		}
+	}
+}
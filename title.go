@@ -0,0 +1,113 @@
+package termdeco
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// PushTitle saves the window title on the terminal's stack and
+	// PopTitle restores it, so a program can set its title temporarily.
+	PushTitle = "\x1b[22;0t"
+	PopTitle  = "\x1b[23;0t"
+)
+
+// SetTitle returns a sequence to set the window title and the tab or icon
+// title. Control characters in title are removed.
+func SetTitle(title string) string {
+	return oscStart + "0;" + Sanitize(title, SanitizeStrip) + oscEnd
+}
+
+// SetWindowTitle returns a sequence to set only the window title.
+func SetWindowTitle(title string) string {
+	return oscStart + "2;" + Sanitize(title, SanitizeStrip) + oscEnd
+}
+
+// ReportDirectory returns an OSC 7 sequence to tell the terminal the current
+// directory is dir, or the working directory if dir is empty. Terminals open
+// new tabs in it.
+func ReportDirectory(dir string) string {
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	host, _ := os.Hostname()
+	return oscStart + "7;" + fileURL(host, dir) + oscEnd
+}
+
+func fileURL(host, path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// a Windows path like C:/Users
+		path = "/" + path
+	}
+	u := url.URL{Scheme: "file", Host: host, Path: path}
+	return u.String()
+}
+
+// NotifyProtocol is a protocol of desktop notifications.
+type NotifyProtocol int
+
+const (
+	// NotifyBell rings the bell, which most terminals show as an attention
+	// mark.
+	NotifyBell NotifyProtocol = iota
+	// NotifyOSC9 is OSC 9 of iTerm2, Windows Terminal, kitty and others.
+	// It has only a message.
+	NotifyOSC9
+	// NotifyOSC777 is OSC 777 of VTE based terminals, urxvt and others. It
+	// has a title and a body.
+	NotifyOSC777
+)
+
+// Notification is the protocol used by Notify. It is detected from the
+// environment at startup.
+var Notification = detectNotification(os.Getenv)
+
+// Notify returns a sequence to show a desktop notification of title and
+// body with Notification. Inside tmux or GNU screen, the sequence is passed
+// through to the outer terminal.
+func Notify(title, body string) string {
+	title = Sanitize(title, SanitizeStrip)
+	body = Sanitize(body, SanitizeStrip)
+	switch Notification {
+	case NotifyOSC9:
+		msg := body
+		if title != "" && body != "" {
+			msg = title + ": " + body
+		} else if title != "" {
+			msg = title
+		}
+		return passthrough(oscStart + "9;" + msg + oscEnd)
+	case NotifyOSC777:
+		return passthrough(oscStart + "777;notify;" + strings.ReplaceAll(title, ";", ",") + ";" + body + oscEnd)
+	}
+	return bel
+}
+
+func detectNotification(getenv func(string) string) NotifyProtocol {
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "ghostty":
+		return NotifyOSC9
+	case "WezTerm":
+		return NotifyOSC777
+	}
+	if getenv("WT_SESSION") != "" || getenv("KITTY_WINDOW_ID") != "" || getenv("ConEmuANSI") == "ON" {
+		return NotifyOSC9
+	}
+	if getenv("VTE_VERSION") != "" {
+		return NotifyOSC777
+	}
+	term := getenv("TERM")
+	switch {
+	case strings.HasPrefix(term, "xterm-kitty"), strings.HasPrefix(term, "xterm-ghostty"):
+		return NotifyOSC9
+	case strings.HasPrefix(term, "rxvt-unicode"), strings.HasPrefix(term, "foot"):
+		return NotifyOSC777
+	}
+	return NotifyBell
+}
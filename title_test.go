@@ -0,0 +1,79 @@
+package termdeco
+
+import "testing"
+
+func TestTitle(t *testing.T) {
+	if got, expected := SetTitle("build\x1b]0;x\a: ok"), "\x1b]0;build: ok\x1b\\"; got != expected {
+		t.Errorf("SetTitle = %q, expected %q", got, expected)
+	}
+	if got, expected := SetWindowTitle("編集"), "\x1b]2;編集\x1b\\"; got != expected {
+		t.Errorf("SetWindowTitle = %q, expected %q", got, expected)
+	}
+}
+
+func TestFileURL(t *testing.T) {
+	tests := []struct {
+		host, path, expected string
+	}{
+		{"box", "/home/me/src", "file://box/home/me/src"},
+		{"box", "/tmp/a b/100%#?", "file://box/tmp/a%20b/100%25%23%3F"},
+		{"", "/", "file:///"},
+		{"pc", "C:/Users/me", "file://pc/C:/Users/me"},
+	}
+	for _, tt := range tests {
+		if got := fileURL(tt.host, tt.path); got != tt.expected {
+			t.Errorf("fileURL(%q, %q) = %q, expected %q", tt.host, tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestNotify(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("STY", "")
+	t.Setenv("TERM", "xterm")
+	old := Notification
+	defer func() { Notification = old }()
+
+	tests := []struct {
+		protocol    NotifyProtocol
+		title, body string
+		expected    string
+	}{
+		{NotifyBell, "build", "done", "\a"},
+		{NotifyOSC9, "build", "done", "\x1b]9;build: done\x1b\\"},
+		{NotifyOSC9, "", "done", "\x1b]9;done\x1b\\"},
+		{NotifyOSC777, "a;b", "done\a", "\x1b]777;notify;a,b;done\x1b\\"},
+	}
+	for _, tt := range tests {
+		Notification = tt.protocol
+		if got := Notify(tt.title, tt.body); got != tt.expected {
+			t.Errorf("Notify(%q, %q) with %d = %q, expected %q", tt.title, tt.body, tt.protocol, got, tt.expected)
+		}
+	}
+
+	Notification = NotifyOSC9
+	t.Setenv("TMUX", "1")
+	if got, expected := Notify("", "x"), "\x1bPtmux;\x1b\x1b]9;x\x1b\x1b\\\x1b\\"; got != expected {
+		t.Errorf("Notify in tmux = %q, expected %q", got, expected)
+	}
+}
+
+func TestDetectNotification(t *testing.T) {
+	tests := []struct {
+		env      map[string]string
+		expected NotifyProtocol
+	}{
+		{map[string]string{"TERM": "xterm-256color"}, NotifyBell},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, NotifyOSC9},
+		{map[string]string{"WT_SESSION": "x"}, NotifyOSC9},
+		{map[string]string{"VTE_VERSION": "7000"}, NotifyOSC777},
+		{map[string]string{"TERM": "rxvt-unicode-256color"}, NotifyOSC777},
+		{map[string]string{"TERM": "xterm-kitty"}, NotifyOSC9},
+	}
+	for i, tt := range tests {
+		getenv := func(key string) string { return tt.env[key] }
+		if got := detectNotification(getenv); got != tt.expected {
+			t.Errorf("[%d] detectNotification(%v) = %d, expected %d", i, tt.env, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// tree is a package to print hierarchical data as a tree like
+//
+//	project
+//	├── cmd
+//	│   └── main.go
+//	└── go.mod
+//
+// Nodes are built explicitly with New and Add or from any Go value with
+// FromValue, and printed by a Printer. Labels of nodes are decorated by
+// termdeco and their styles are kept intact across the branch glyphs.
+package tree
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tatsushid/termdeco"
+)
+
+// Glyphs is strings to draw branches. All of them should have the same
+// width.
+type Glyphs struct {
+	// Branch and Last are put before a node which has following siblings
+	// and before the last node.
+	Branch, Last string
+	// Vertical and Space are put under Branch and Last.
+	Vertical, Space string
+}
+
+var (
+	// GlyphsUnicode draws branches with box drawing characters.
+	GlyphsUnicode = Glyphs{"├── ", "└── ", "│   ", "    "}
+	// GlyphsASCII draws branches with ASCII characters.
+	GlyphsASCII = Glyphs{"|-- ", "`-- ", "|   ", "    "}
+)
+
+// Node is a node of a tree.
+type Node struct {
+	// Value is the label. Decorators and the other fmt.Formatters are
+	// printed by termdeco.Sprint and the other values are sanitized by
+	// termdeco.Sanitize like values of Decorators, so styles are given only
+	// by Decorators.
+	Value interface{}
+	// Style is applied to the label.
+	Style *termdeco.Decorator
+	// Annotation is printed like Value at the right end of the line if it
+	// isn't nil.
+	Annotation interface{}
+	// Collapsed hides children and shows Printer.CollapsedMarker instead.
+	Collapsed bool
+	Children  []*Node
+}
+
+// New returns a node of v.
+func New(v interface{}) *Node {
+	return &Node{Value: v}
+}
+
+// Add adds a child node of v and returns it.
+func (n *Node) Add(v interface{}) *Node {
+	c := New(v)
+	n.Children = append(n.Children, c)
+	return c
+}
+
+// AddNode adds nodes as children and returns n.
+func (n *Node) AddNode(nodes ...*Node) *Node {
+	n.Children = append(n.Children, nodes...)
+	return n
+}
+
+// Printer prints trees.
+type Printer struct {
+	Glyphs     Glyphs
+	GlyphStyle *termdeco.Decorator
+	// CollapsedMarker is appended to the label of a collapsed node which
+	// has children. A %d in it is replaced by the number of hidden
+	// children.
+	CollapsedMarker string
+	AnnotationStyle *termdeco.Decorator
+	// Width is the column which annotations are aligned to the right. If
+	// it is 0, they are aligned just after the longest annotated line.
+	Width int
+}
+
+// NewPrinter returns a Printer with GlyphsUnicode and a collapsed marker like
+// " [+3]".
+func NewPrinter() *Printer {
+	return &Printer{
+		Glyphs:          GlyphsUnicode,
+		CollapsedMarker: " [+%d]",
+	}
+}
+
+// line is a printed line of a tree.
+type line struct {
+	prefix, label, annotation string
+}
+
+// String returns the printed tree of n.
+func (p *Printer) String(n *Node) string {
+	var lines []line
+	p.walk(n, "", "", &lines)
+
+	width := p.Width
+	if width == 0 {
+		for _, l := range lines {
+			if l.annotation == "" {
+				continue
+			}
+			if w := termdeco.Width(l.prefix+l.label) + 2 + termdeco.Width(l.annotation); w > width {
+				width = w
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(apply(p.GlyphStyle, l.prefix))
+		b.WriteString(l.label)
+		if l.annotation != "" {
+			gap := width - termdeco.Width(l.prefix+l.label) - termdeco.Width(l.annotation)
+			if gap < 1 {
+				gap = 1
+			}
+			b.WriteString(strings.Repeat(" ", gap))
+			b.WriteString(apply(p.AnnotationStyle, l.annotation))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Render writes the printed tree of n to w.
+func (p *Printer) Render(w io.Writer, n *Node) error {
+	_, err := termdeco.Fprint(w, p.String(n))
+	return err
+}
+
+// format prints v for a label or an annotation. Only fmt.Formatters like
+// Decorators can print escape sequences.
+func format(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case fmt.Formatter:
+		return termdeco.Sprint(v)
+	}
+	return termdeco.Sanitize(fmt.Sprint(v), termdeco.SanitizeDefault)
+}
+
+func (p *Printer) walk(n *Node, first, rest string, lines *[]line) {
+	label := apply(n.Style, format(n.Value))
+	children := n.Children
+	if n.Collapsed && len(children) > 0 {
+		marker := p.CollapsedMarker
+		if strings.Contains(marker, "%d") {
+			marker = fmt.Sprintf(marker, len(children))
+		}
+		label += marker
+		children = nil
+	}
+	annotation := format(n.Annotation)
+
+	// following lines of a multi-line label are aligned with its first line
+	for i, l := range termdeco.Split(label) {
+		if i == 0 {
+			*lines = append(*lines, line{first, l, annotation})
+		} else {
+			*lines = append(*lines, line{rest, l, ""})
+		}
+	}
+
+	for i, c := range children {
+		if i == len(children)-1 {
+			p.walk(c, rest+p.Glyphs.Last, rest+p.Glyphs.Space, lines)
+		} else {
+			p.walk(c, rest+p.Glyphs.Branch, rest+p.Glyphs.Vertical, lines)
+		}
+	}
+}
+
+func apply(d *termdeco.Decorator, s string) string {
+	if d == nil {
+		return s
+	}
+	return d.Apply(s)
+}
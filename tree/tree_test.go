@@ -0,0 +1,161 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/tatsushid/termdeco"
+	"github.com/tatsushid/termdeco/termdecotest"
+)
+
+func sample() *Node {
+	root := New("project")
+	cmd := root.Add("cmd")
+	cmd.Add("main.go")
+	cmd.Add("util.go")
+	root.Add("go.mod")
+	return root
+}
+
+func TestPrinter(t *testing.T) {
+	p := NewPrinter()
+	expected := "" +
+		"project\n" +
+		"├── cmd\n" +
+		"│   ├── main.go\n" +
+		"│   └── util.go\n" +
+		"└── go.mod\n"
+	if s := p.String(sample()); s != expected {
+		t.Errorf("String =\n%s\nexpected\n%s", s, expected)
+	}
+
+	p.Glyphs = GlyphsASCII
+	root := sample()
+	root.Children[0].Collapsed = true
+	expected = "" +
+		"project\n" +
+		"|-- cmd [+2]\n" +
+		"`-- go.mod\n"
+	if s := p.String(root); s != expected {
+		t.Errorf("String =\n%s\nexpected\n%s", s, expected)
+	}
+}
+
+func TestAnnotationAndMultiline(t *testing.T) {
+	root := New("deps")
+	root.Add("termdeco").Annotation = "v1.2.0"
+	n := root.Add("sub\nmodule")
+	n.Annotation = "v10.0.0"
+	n.Add("x")
+	expected := "" +
+		"deps\n" +
+		"├── termdeco  v1.2.0\n" +
+		"└── sub      v10.0.0\n" +
+		"    module\n" +
+		"    └── x\n"
+	if s := NewPrinter().String(root); s != expected {
+		t.Errorf("String =\n%s\nexpected\n%s", s, expected)
+	}
+}
+
+func TestStyles(t *testing.T) {
+	root := New(termdeco.Bold("root"))
+	root.Add("error\ndetail").Style = termdeco.Red(nil)
+	p := NewPrinter()
+	p.GlyphStyle = termdeco.BrightBlack(nil)
+	s := p.String(root)
+	termdecotest.AssertClean(t, s)
+	termdecotest.AssertStyled(t, s, termdeco.Sprintf("%v\n%v%v\n%v%v\n",
+		termdeco.Bold("root"),
+		termdeco.BrightBlack("└── "), termdeco.Red("error"),
+		termdeco.BrightBlack("    "), termdeco.Red("detail")))
+}
+
+func TestFromValue(t *testing.T) {
+	type server struct {
+		Name  string
+		Ports []int
+		Tags  map[string]string
+		Next  *server
+		note  string
+	}
+	v := &server{Name: "web\x1b[2J", Ports: []int{80, 443}, Tags: map[string]string{"z": "1", "a": "2"}}
+	v.Next = v
+	expected := "" +
+		"*tree.server\n" +
+		"├── Name: web\\x1b[2J\n" +
+		"├── Ports\n" +
+		"│   ├── 80\n" +
+		"│   └── 443\n" +
+		"├── Tags\n" +
+		"│   ├── a: 2\n" +
+		"│   └── z: 1\n" +
+		"└── Next\n" +
+		"    └── <cycle>\n"
+	if s := NewPrinter().String(FromValue(v)); s != expected {
+		t.Errorf("String =\n%s\nexpected\n%s", s, expected)
+	}
+}
+
+func TestSanitizedLabels(t *testing.T) {
+	root := New("evil\x1b[2J")
+	root.Annotation = "\x1b]0;pwned\x07"
+	root.Add(termdeco.Red("ok"))
+	expected := `evil\x1b[2J  \x1b]0;pwned\x07` + "\n" +
+		"└── \x1b[31mok\x1b[0m\n"
+	if s := NewPrinter().String(root); s != expected {
+		t.Errorf("String =\n%q\nexpected\n%q", s, expected)
+	}
+}
+
+func TestFromValueStyledAndNodes(t *testing.T) {
+	sub := New("sub")
+	sub.Add("leaf")
+	v := map[string]interface{}{
+		"status": termdeco.Red("ok"),
+		"name":   "x\x1b[2J",
+		"node":   sub,
+		"list":   []interface{}{sub},
+	}
+	expected := "" +
+		"map[string]interface {}\n" +
+		"├── list\n" +
+		"│   └── sub\n" +
+		"│       └── leaf\n" +
+		"├── name: x\\x1b[2J\n" +
+		"├── node\n" +
+		"│   └── sub\n" +
+		"│       └── leaf\n" +
+		"└── status: \x1b[31mok\x1b[0m\n"
+	if s := NewPrinter().String(FromValue(v)); s != expected {
+		t.Errorf("String =\n%q\nexpected\n%q", s, expected)
+	}
+}
+
+func TestFromValueNilNode(t *testing.T) {
+	if s := NewPrinter().String(FromValue((*Node)(nil))); s != "<nil>\n" {
+		t.Errorf("String = %q, expected %q", s, "<nil>\n")
+	}
+}
+
+func TestFromValueContainerCycle(t *testing.T) {
+	m := map[string]interface{}{"a": 1}
+	m["self"] = m
+	s := []interface{}{1, nil}
+	s[1] = s
+	expected := "" +
+		"map[string]interface {}\n" +
+		"├── a: 1\n" +
+		"└── self\n" +
+		"    └── <cycle>\n"
+	if got := NewPrinter().String(FromValue(m)); got != expected {
+		t.Errorf("String =\n%s\nexpected\n%s", got, expected)
+	}
+	expected = "" +
+		"[]interface {}\n" +
+		"├── 1\n" +
+		"└── [1]\n" +
+		"    └── <cycle>\n"
+	if got := NewPrinter().String(FromValue(s)); got != expected {
+		t.Errorf("String =\n%s\nexpected\n%s", got, expected)
+	}
+}
@@ -0,0 +1,168 @@
+package tree
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/tatsushid/termdeco"
+)
+
+// FromValue returns a tree of v walked by reflection. Maps, structs, slices
+// and arrays become nodes labeled by their type, keys, field names or
+// indexes and the other values become leaves like "key: value". Map keys are
+// sorted, unexported struct fields are skipped and a non-nil *Node is used as
+// is. Values implementing fmt.Formatter or fmt.Stringer are leaves.
+// Formatters like *termdeco.Decorator are printed by termdeco.Sprint so their
+// styles are kept, and the other leaves are sanitized by termdeco.Sanitize.
+func FromValue(v interface{}) *Node {
+	if n, ok := v.(*Node); ok && n != nil {
+		return n
+	}
+	rv := reflect.ValueOf(v)
+	if isLeaf(rv) {
+		return New(label(leaf(rv)))
+	}
+	n := New(rv.Type().String())
+	addChildren(n, rv, map[visit]bool{})
+	return n
+}
+
+// visit is a pointer, map or slice being walked, which makes a cycle if it
+// is found again inside itself.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// asNode returns the *Node in rv if any.
+func asNode(rv reflect.Value) (*Node, bool) {
+	if !rv.IsValid() || !rv.CanInterface() {
+		return nil, false
+	}
+	n, ok := rv.Interface().(*Node)
+	return n, ok && n != nil
+}
+
+func isLeaf(rv reflect.Value) bool {
+	if !rv.IsValid() {
+		return true
+	}
+	if rv.CanInterface() {
+		switch rv.Interface().(type) {
+		case fmt.Formatter, fmt.Stringer, error:
+			return true
+		}
+	}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return true
+		}
+		return isLeaf(rv.Elem())
+	case reflect.Map, reflect.Struct, reflect.Slice, reflect.Array:
+		return false
+	}
+	return true
+}
+
+// label returns s built from leaves as a label printed as is, so styles of
+// leaves are kept.
+func label(s string) *termdeco.Decorator {
+	return (&termdeco.Decorator{Value: s}).Trusted()
+}
+
+func leaf(rv reflect.Value) string {
+	if !rv.IsValid() {
+		return "<nil>"
+	}
+	if rv.CanInterface() {
+		v := rv.Interface()
+		if f, ok := v.(fmt.Formatter); ok {
+			return termdeco.Sprint(f)
+		}
+		return termdeco.Sanitize(fmt.Sprint(v), termdeco.SanitizeDefault)
+	}
+	return termdeco.Sanitize(fmt.Sprint(rv), termdeco.SanitizeDefault)
+}
+
+// child adds a child node of rv labeled by name to n.
+func child(n *Node, name string, rv reflect.Value, seen map[visit]bool) {
+	if c, ok := asNode(rv); ok {
+		n.Add(label(name)).AddNode(c)
+		return
+	}
+	if isLeaf(rv) {
+		n.Add(label(name + ": " + leaf(rv)))
+		return
+	}
+	addChildren(n.Add(label(name)), rv, seen)
+}
+
+// enter marks rv as being walked. If it is already, it adds a "<cycle>"
+// node to n and returns false.
+func enter(n *Node, rv reflect.Value, seen map[visit]bool) bool {
+	v := visit{rv.Pointer(), rv.Type()}
+	if seen[v] {
+		n.Add("<cycle>")
+		return false
+	}
+	seen[v] = true
+	return true
+}
+
+func leave(rv reflect.Value, seen map[visit]bool) {
+	delete(seen, visit{rv.Pointer(), rv.Type()})
+}
+
+func addChildren(n *Node, rv reflect.Value, seen map[visit]bool) {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.Kind() == reflect.Ptr {
+			if !enter(n, rv, seen) {
+				return
+			}
+			defer leave(rv, seen)
+		}
+		rv = rv.Elem()
+	}
+	if (rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice) && rv.Pointer() != 0 {
+		if !enter(n, rv, seen) {
+			return
+		}
+		defer leave(rv, seen)
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		keys := rv.MapKeys()
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = leaf(k)
+		}
+		order := make([]int, len(keys))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool { return names[order[i]] < names[order[j]] })
+		for _, i := range order {
+			child(n, names[i], rv.MapIndex(keys[i]), seen)
+		}
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath == "" {
+				child(n, f.Name, rv.Field(i), seen)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			e := rv.Index(i)
+			if c, ok := asNode(e); ok {
+				n.AddNode(c)
+			} else if isLeaf(e) {
+				n.Add(label(leaf(e)))
+			} else {
+				addChildren(n.Add(fmt.Sprintf("[%d]", i)), e, seen)
+			}
+		}
+	}
+}
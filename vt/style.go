@@ -0,0 +1,179 @@
+package vt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Color is a text or background color of a cell. The zero value is the
+// terminal's default color.
+type Color uint32
+
+const (
+	// DefaultColor is the terminal's default foreground or background
+	// color.
+	DefaultColor Color = 0
+
+	colorIndexed = 1 << 24
+	colorRGB     = 2 << 24
+)
+
+// IndexedColor returns a color of the 256 color palette. 0 to 7 are the
+// normal ANSI colors and 8 to 15 are their bright variants.
+func IndexedColor(i uint8) Color { return colorIndexed | Color(i) }
+
+// RGBColor returns a 24-bit color.
+func RGBColor(r, g, b uint8) Color {
+	return colorRGB | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// Index returns the palette index of c and whether c is an indexed color.
+func (c Color) Index() (uint8, bool) {
+	return uint8(c), c&^0xffffff == colorIndexed
+}
+
+// RGB returns the components of c and whether c is a 24-bit color.
+func (c Color) RGB() (r, g, b uint8, ok bool) {
+	return uint8(c >> 16), uint8(c >> 8), uint8(c), c&^0xffffff == colorRGB
+}
+
+var colorNames = [8]string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// String returns a readable name of c like "red", "bright-red", "color(200)"
+// or "#ff8000".
+func (c Color) String() string {
+	if r, g, b, ok := c.RGB(); ok {
+		return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	}
+	i, ok := c.Index()
+	switch {
+	case !ok:
+		return "default"
+	case i < 8:
+		return colorNames[i]
+	case i < 16:
+		return "bright-" + colorNames[i-8]
+	}
+	return fmt.Sprintf("color(%d)", i)
+}
+
+// Attr is a set of text attributes of a cell.
+type Attr uint16
+
+const (
+	Bold Attr = 1 << iota
+	Faint
+	Italic
+	Underline
+	Blink
+	Reverse
+	Conceal
+	Strikethrough
+)
+
+// Style is colors and attributes of a cell. The zero value is the default
+// style.
+type Style struct {
+	Fg, Bg Color
+	Attrs  Attr
+}
+
+var attrNames = []string{"bold", "faint", "italic", "underline", "blink", "reverse", "conceal", "strikethrough"}
+
+// String returns a readable description of s like "fg:red bg:green bold". It
+// returns "" for the default style.
+func (s Style) String() string {
+	var parts []string
+	if s.Fg != DefaultColor {
+		parts = append(parts, "fg:"+s.Fg.String())
+	}
+	if s.Bg != DefaultColor {
+		parts = append(parts, "bg:"+s.Bg.String())
+	}
+	for i, name := range attrNames {
+		if s.Attrs&(1<<uint(i)) != 0 {
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// ApplySGR returns s updated by the parameters of an SGR sequence.
+func (s Style) ApplySGR(params []int) Style {
+	if len(params) == 0 {
+		return Style{}
+	}
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		switch {
+		case p == 0:
+			s = Style{}
+		case p == 1:
+			s.Attrs |= Bold
+		case p == 2:
+			s.Attrs |= Faint
+		case p == 3:
+			s.Attrs |= Italic
+		case p == 4 || p == 21:
+			s.Attrs |= Underline
+		case p == 5 || p == 6:
+			s.Attrs |= Blink
+		case p == 7:
+			s.Attrs |= Reverse
+		case p == 8:
+			s.Attrs |= Conceal
+		case p == 9:
+			s.Attrs |= Strikethrough
+		case p == 22:
+			s.Attrs &^= Bold | Faint
+		case p == 23:
+			s.Attrs &^= Italic
+		case p == 24:
+			s.Attrs &^= Underline
+		case p == 25:
+			s.Attrs &^= Blink
+		case p == 27:
+			s.Attrs &^= Reverse
+		case p == 28:
+			s.Attrs &^= Conceal
+		case p == 29:
+			s.Attrs &^= Strikethrough
+		case p >= 30 && p <= 37:
+			s.Fg = IndexedColor(uint8(p - 30))
+		case p == 38:
+			s.Fg, i = extendedColor(params, i)
+		case p == 39:
+			s.Fg = DefaultColor
+		case p >= 40 && p <= 47:
+			s.Bg = IndexedColor(uint8(p - 40))
+		case p == 48:
+			s.Bg, i = extendedColor(params, i)
+		case p == 49:
+			s.Bg = DefaultColor
+		case p >= 90 && p <= 97:
+			s.Fg = IndexedColor(uint8(p - 90 + 8))
+		case p >= 100 && p <= 107:
+			s.Bg = IndexedColor(uint8(p - 100 + 8))
+		}
+	}
+	return s
+}
+
+// extendedColor parses 38;5;n and 38;2;r;g;b forms starting at params[i] and
+// returns the color and the index of its last parameter.
+func extendedColor(params []int, i int) (Color, int) {
+	if i+1 >= len(params) {
+		return DefaultColor, len(params)
+	}
+	switch params[i+1] {
+	case 5:
+		if i+2 < len(params) {
+			return IndexedColor(uint8(params[i+2])), i + 2
+		}
+	case 2:
+		if i+4 < len(params) {
+			return RGBColor(uint8(params[i+2]), uint8(params[i+3]), uint8(params[i+4])), i + 4
+		}
+	}
+	return DefaultColor, len(params)
+}
@@ -0,0 +1,698 @@
+// vt is a package of an in-memory terminal emulator which interprets text
+// with ANSI escape sequences, like termdeco's output, the way a terminal does
+// and holds what a user would see.
+//
+// It is mainly for testing. Instead of comparing raw escape sequences, tests
+// can assert the resulting text and styles on the screen like
+//
+//	s := vt.New(80, 24)
+//	termdeco.Fprint(s, termdeco.Red("error").Bold(), ": not found")
+//	s.Line(0)           // "error: not found"
+//	s.Cell(0, 0).Style  // vt.Style{Fg: vt.IndexedColor(1), Attrs: vt.Bold}
+//
+// It supports cursor movement, line wrap, scrolling with scroll regions,
+// insert and erase operations and the alternate screen.
+package vt
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tatsushid/termdeco"
+)
+
+// Cell is a character cell of a screen.
+type Cell struct {
+	// Text is the character in the cell followed by its combining
+	// characters. It is empty for the right half of a wide character.
+	Text  string
+	Style Style
+	// Wide is true if the character occupies this and the next cell.
+	Wide bool
+}
+
+var blankCell = Cell{Text: " "}
+
+// cursor is a cursor state saved by DECSC.
+type cursor struct {
+	x, y        int
+	style       Style
+	wrapPending bool
+}
+
+// Screen is an in-memory terminal screen. It implements io.Writer and
+// everything written to it is interpreted as terminal output.
+type Screen struct {
+	// MaxScrollback is the number of lines scrolled out of the top of the
+	// main screen which are kept. 1000 is used if it is 0 and none is kept
+	// if it is negative.
+	MaxScrollback int
+
+	cols, rows int
+	lines      [][]Cell
+	main, alt  [][]Cell
+	scrollback []string
+	title      string
+
+	cursor
+	saved      cursor
+	altSaved   cursor
+	top, bot   int
+	inAlt      bool
+	hideCursor bool
+	noAutoWrap bool
+	pending    []byte
+}
+
+// New returns a cols x rows blank screen.
+func New(cols, rows int) *Screen {
+	s := &Screen{}
+	s.init(cols, rows)
+	return s
+}
+
+func (s *Screen) init(cols, rows int) {
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	s.cols, s.rows = cols, rows
+	s.main = blankLines(cols, rows)
+	s.alt = nil
+	s.lines = s.main
+	s.inAlt = false
+	s.cursor = cursor{}
+	s.saved = cursor{}
+	s.top, s.bot = 0, rows-1
+	s.hideCursor = false
+	s.noAutoWrap = false
+}
+
+func blankLine(cols int) []Cell {
+	l := make([]Cell, cols)
+	for x := range l {
+		l[x] = blankCell
+	}
+	return l
+}
+
+func blankLines(cols, rows int) [][]Cell {
+	lines := make([][]Cell, rows)
+	for y := range lines {
+		lines[y] = blankLine(cols)
+	}
+	return lines
+}
+
+// Size returns the number of columns and rows of s.
+func (s *Screen) Size() (cols, rows int) { return s.cols, s.rows }
+
+// Resize changes the size of s. Lines and columns out of the new size are
+// dropped from the bottom and right.
+func (s *Screen) Resize(cols, rows int) {
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	resize := func(lines [][]Cell) [][]Cell {
+		if lines == nil {
+			return nil
+		}
+		n := blankLines(cols, rows)
+		for y := 0; y < rows && y < len(lines); y++ {
+			copy(n[y], lines[y])
+			if last := &n[y][cols-1]; last.Wide {
+				*last = blankCell
+			}
+		}
+		return n
+	}
+	s.main, s.alt = resize(s.main), resize(s.alt)
+	s.lines = s.main
+	if s.inAlt {
+		s.lines = s.alt
+	}
+	s.cols, s.rows = cols, rows
+	s.top, s.bot = 0, rows-1
+	s.wrapPending = false
+	s.x = clamp(s.x, 0, cols-1)
+	s.y = clamp(s.y, 0, rows-1)
+}
+
+// Cell returns the cell at column x and row y, both 0-based.
+func (s *Screen) Cell(x, y int) Cell {
+	if x < 0 || x >= s.cols || y < 0 || y >= s.rows {
+		return blankCell
+	}
+	return s.lines[y][x]
+}
+
+// Line returns the text of row y without trailing spaces.
+func (s *Screen) Line(y int) string {
+	if y < 0 || y >= s.rows {
+		return ""
+	}
+	return lineText(s.lines[y])
+}
+
+func lineText(l []Cell) string {
+	var b strings.Builder
+	for _, c := range l {
+		b.WriteString(c.Text)
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// String returns the text of the screen, lines separated by "\n" without
+// trailing spaces and trailing empty lines.
+func (s *Screen) String() string {
+	lines := make([]string, s.rows)
+	for y := range lines {
+		lines[y] = s.Line(y)
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// Scrollback returns the text of lines scrolled out of the main screen,
+// oldest first.
+func (s *Screen) Scrollback() []string {
+	return s.scrollback
+}
+
+// Cursor returns the cursor position and whether the cursor is visible.
+func (s *Screen) Cursor() (x, y int, visible bool) {
+	return s.x, s.y, !s.hideCursor
+}
+
+// AltScreen reports whether the alternate screen is active.
+func (s *Screen) AltScreen() bool {
+	return s.inAlt
+}
+
+// Title returns the window title set by OSC 0 or 2.
+func (s *Screen) Title() string {
+	return s.title
+}
+
+// Write interprets p as terminal output. An escape sequence or a UTF-8
+// encoded character split across writes is kept until it is completed by
+// following writes. It never returns an error.
+func (s *Screen) Write(p []byte) (n int, err error) {
+	buf := string(append(s.pending, p...))
+	s.pending = nil
+	for buf != "" {
+		t := termdeco.NextToken(buf)
+		if t.Incomplete && len(t.Raw) == len(buf) {
+			s.pending = []byte(buf)
+			break
+		}
+		if t.Kind == termdeco.TextToken && len(t.Raw) == len(buf) {
+			last := lastRuneStart(buf)
+			if !utf8.FullRuneInString(last) {
+				s.text(buf[:len(buf)-len(last)])
+				s.pending = []byte(last)
+				break
+			}
+		}
+		s.token(t)
+		buf = buf[len(t.Raw):]
+	}
+	return len(p), nil
+}
+
+// lastRuneStart returns the suffix of s starting at its last rune.
+func lastRuneStart(s string) string {
+	for i := len(s) - 1; i >= 0 && i >= len(s)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(s[i]) {
+			return s[i:]
+		}
+	}
+	return s[len(s)-1:]
+}
+
+func (s *Screen) token(t termdeco.Token) {
+	switch t.Kind {
+	case termdeco.TextToken:
+		s.text(t.Raw)
+	case termdeco.ControlToken:
+		r, _ := utf8.DecodeRuneInString(t.Raw)
+		s.control(r)
+	case termdeco.CSIToken:
+		if !t.Incomplete {
+			s.csi(t)
+		}
+	case termdeco.EscapeToken:
+		if !t.Incomplete {
+			s.esc(t)
+		}
+	case termdeco.OSCToken:
+		p := t.Payload()
+		if strings.HasPrefix(p, "0;") || strings.HasPrefix(p, "2;") {
+			s.title = p[2:]
+		}
+	}
+}
+
+func (s *Screen) text(str string) {
+	for str != "" {
+		g, w := termdeco.NextGrapheme(str)
+		s.put(g, w)
+		str = str[len(g):]
+	}
+}
+
+// put writes a grapheme cluster g occupying w columns at the cursor.
+func (s *Screen) put(g string, w int) {
+	if w == 0 {
+		s.combine(g)
+		return
+	}
+	if s.wrapPending {
+		s.wrapPending = false
+		s.x = 0
+		s.lineFeed()
+	}
+	if w == 2 && s.x == s.cols-1 {
+		if s.noAutoWrap || s.cols < 2 {
+			return
+		}
+		s.clearCell(s.x, s.y)
+		s.x = 0
+		s.lineFeed()
+	}
+	s.clearCell(s.x, s.y)
+	if w == 2 {
+		s.clearCell(s.x+1, s.y)
+		s.lines[s.y][s.x+1] = Cell{Style: s.style}
+	}
+	s.lines[s.y][s.x] = Cell{Text: g, Style: s.style, Wide: w == 2}
+	if s.x+w >= s.cols {
+		s.x = s.cols - 1
+		s.wrapPending = !s.noAutoWrap
+	} else {
+		s.x += w
+	}
+}
+
+// combine appends zero-width characters to the last written character.
+func (s *Screen) combine(g string) {
+	x := s.x
+	if !s.wrapPending {
+		x--
+	}
+	if x > 0 && s.lines[s.y][x].Text == "" {
+		x--
+	}
+	if x < 0 {
+		return
+	}
+	s.lines[s.y][x].Text += g
+}
+
+// clearCell blanks the cell at (x, y). If it is a half of a wide character,
+// the other half is also blanked.
+func (s *Screen) clearCell(x, y int) {
+	l := s.lines[y]
+	if l[x].Wide && x+1 < s.cols {
+		l[x+1] = blankCell
+	} else if l[x].Text == "" && x > 0 {
+		l[x-1] = blankCell
+	}
+	l[x] = blankCell
+}
+
+// splitWide blanks the wide character at (x-1, y) if the cell at (x, y) is
+// its right half.
+func (s *Screen) splitWide(x, y int) {
+	l := s.lines[y]
+	if l[x].Text == "" && x > 0 {
+		l[x-1] = blankCell
+		l[x] = blankCell
+	}
+}
+
+func (s *Screen) control(r rune) {
+	switch r {
+	case '\r':
+		s.x = 0
+		s.wrapPending = false
+	case '\n', '\v', '\f':
+		s.lineFeed()
+	case '\b':
+		if s.x > 0 && !s.wrapPending {
+			s.x--
+		}
+		s.wrapPending = false
+	case '\t':
+		s.x = (s.x/8 + 1) * 8
+		if s.x >= s.cols {
+			s.x = s.cols - 1
+		}
+	case 0x84: // IND
+		s.lineFeed()
+	case 0x85: // NEL
+		s.x = 0
+		s.lineFeed()
+	case 0x8d: // RI
+		s.reverseLineFeed()
+	}
+}
+
+func (s *Screen) lineFeed() {
+	s.wrapPending = false
+	if s.y == s.bot {
+		s.scrollUp(s.top, s.bot, 1, true)
+	} else if s.y < s.rows-1 {
+		s.y++
+	}
+}
+
+func (s *Screen) reverseLineFeed() {
+	s.wrapPending = false
+	if s.y == s.top {
+		s.scrollDown(s.top, s.bot, 1)
+	} else if s.y > 0 {
+		s.y--
+	}
+}
+
+// scrollUp scrolls lines from top to bot up n lines. If keep is true, lines
+// scrolled out of the top of the main screen are kept as scrollback.
+func (s *Screen) scrollUp(top, bot, n int, keep bool) {
+	n = clamp(n, 0, bot-top+1)
+	if keep && top == 0 && !s.inAlt {
+		for _, l := range s.lines[:n] {
+			s.pushScrollback(lineText(l))
+		}
+	}
+	region := s.lines[top : bot+1]
+	copy(region, region[n:])
+	for i := len(region) - n; i < len(region); i++ {
+		region[i] = s.blankLine()
+	}
+}
+
+// scrollDown scrolls lines from top to bot down n lines.
+func (s *Screen) scrollDown(top, bot, n int) {
+	n = clamp(n, 0, bot-top+1)
+	region := s.lines[top : bot+1]
+	copy(region[n:], region)
+	for i := 0; i < n; i++ {
+		region[i] = s.blankLine()
+	}
+}
+
+func (s *Screen) pushScrollback(line string) {
+	max := s.MaxScrollback
+	if max == 0 {
+		max = 1000
+	}
+	if max < 0 {
+		return
+	}
+	s.scrollback = append(s.scrollback, line)
+	if len(s.scrollback) > max {
+		s.scrollback = s.scrollback[len(s.scrollback)-max:]
+	}
+}
+
+// blankLine returns a line erased with the current background color.
+func (s *Screen) blankLine() []Cell {
+	l := make([]Cell, s.cols)
+	for x := range l {
+		l[x] = s.erasedCell()
+	}
+	return l
+}
+
+func (s *Screen) erasedCell() Cell {
+	return Cell{Text: " ", Style: Style{Bg: s.style.Bg}}
+}
+
+// param returns params[i] or def if it is omitted or 0.
+func param(params []int, i, def int) int {
+	if i < len(params) && params[i] > 0 {
+		return params[i]
+	}
+	return def
+}
+
+func (s *Screen) moveTo(x, y int) {
+	s.wrapPending = false
+	s.x = clamp(x, 0, s.cols-1)
+	s.y = clamp(y, 0, s.rows-1)
+}
+
+// moveVertically moves the cursor n lines, negative n for up, stopping at
+// the margin of the scroll region if the cursor is in it.
+func (s *Screen) moveVertically(n int) {
+	min, max := 0, s.rows-1
+	if s.y >= s.top && s.y <= s.bot {
+		min, max = s.top, s.bot
+	}
+	s.moveTo(s.x, clamp(s.y+n, min, max))
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+func (s *Screen) esc(t termdeco.Token) {
+	if t.Intermediate() != "" {
+		return
+	}
+	switch t.Final() {
+	case '7':
+		s.saved = s.cursor
+	case '8':
+		s.restoreCursor()
+	case 'D':
+		s.lineFeed()
+	case 'E':
+		s.x = 0
+		s.lineFeed()
+	case 'M':
+		s.reverseLineFeed()
+	case 'c':
+		s.init(s.cols, s.rows)
+		s.title = ""
+	}
+}
+
+func (s *Screen) restoreCursor() {
+	s.cursor = s.saved
+	s.x = clamp(s.x, 0, s.cols-1)
+	s.y = clamp(s.y, 0, s.rows-1)
+}
+
+func (s *Screen) csi(t termdeco.Token) {
+	params := t.Params()
+	switch t.Private() {
+	case '?':
+		if t.Final() == 'h' || t.Final() == 'l' {
+			for _, p := range params {
+				s.setMode(p, t.Final() == 'h')
+			}
+		}
+		return
+	case 0:
+	default:
+		return
+	}
+	if t.Intermediate() != "" {
+		return
+	}
+	switch t.Final() {
+	case 'A':
+		s.moveVertically(-param(params, 0, 1))
+	case 'B', 'e':
+		s.moveVertically(param(params, 0, 1))
+	case 'C', 'a':
+		s.moveTo(s.x+param(params, 0, 1), s.y)
+	case 'D':
+		s.moveTo(s.x-param(params, 0, 1), s.y)
+	case 'E':
+		s.moveVertically(param(params, 0, 1))
+		s.x = 0
+	case 'F':
+		s.moveVertically(-param(params, 0, 1))
+		s.x = 0
+	case 'G', '`':
+		s.moveTo(param(params, 0, 1)-1, s.y)
+	case 'd':
+		s.moveTo(s.x, param(params, 0, 1)-1)
+	case 'H', 'f':
+		s.moveTo(param(params, 1, 1)-1, param(params, 0, 1)-1)
+	case 'J':
+		s.eraseDisplay(param(params, 0, 0))
+	case 'K':
+		s.eraseLine(param(params, 0, 0))
+	case 'L':
+		s.insertLines(param(params, 0, 1))
+	case 'M':
+		s.deleteLines(param(params, 0, 1))
+	case '@':
+		s.insertChars(param(params, 0, 1))
+	case 'P':
+		s.deleteChars(param(params, 0, 1))
+	case 'X':
+		n := param(params, 0, 1)
+		s.erase(s.y, s.x, clamp(s.x+n, 0, s.cols))
+		s.wrapPending = false
+	case 'S':
+		s.scrollUp(s.top, s.bot, param(params, 0, 1), true)
+	case 'T':
+		s.scrollDown(s.top, s.bot, param(params, 0, 1))
+	case 'r':
+		top, bot := param(params, 0, 1)-1, param(params, 1, s.rows)-1
+		if top < bot && bot < s.rows {
+			s.top, s.bot = top, bot
+			s.moveTo(0, 0)
+		}
+	case 's':
+		s.saved = s.cursor
+	case 'u':
+		s.restoreCursor()
+	case 'm':
+		s.style = s.style.ApplySGR(params)
+	}
+}
+
+func (s *Screen) setMode(mode int, set bool) {
+	switch mode {
+	case 7:
+		s.noAutoWrap = !set
+		if !set {
+			s.wrapPending = false
+		}
+	case 25:
+		s.hideCursor = !set
+	case 47, 1047:
+		s.switchScreen(set, false)
+	case 1049:
+		s.switchScreen(set, true)
+	}
+}
+
+// switchScreen enters or leaves the alternate screen. If saveCursor is true,
+// the cursor is saved when entering it and restored when leaving it.
+func (s *Screen) switchScreen(alt, saveCursor bool) {
+	if alt == s.inAlt {
+		return
+	}
+	s.inAlt = alt
+	if alt {
+		if saveCursor {
+			s.altSaved = s.cursor
+		}
+		s.alt = blankLines(s.cols, s.rows)
+		s.lines = s.alt
+		return
+	}
+	s.lines = s.main
+	s.alt = nil
+	if saveCursor {
+		s.cursor = s.altSaved
+		s.x = clamp(s.x, 0, s.cols-1)
+		s.y = clamp(s.y, 0, s.rows-1)
+	}
+}
+
+func (s *Screen) insertLines(n int) {
+	if s.y < s.top || s.y > s.bot {
+		return
+	}
+	s.scrollDown(s.y, s.bot, n)
+	s.x = 0
+	s.wrapPending = false
+}
+
+func (s *Screen) deleteLines(n int) {
+	if s.y < s.top || s.y > s.bot {
+		return
+	}
+	s.scrollUp(s.y, s.bot, n, false)
+	s.x = 0
+	s.wrapPending = false
+}
+
+func (s *Screen) insertChars(n int) {
+	l := s.lines[s.y]
+	n = clamp(n, 0, s.cols-s.x)
+	s.splitWide(s.x, s.y)
+	copy(l[s.x+n:], l[s.x:])
+	for x := s.x; x < s.x+n; x++ {
+		l[x] = s.erasedCell()
+	}
+	if l[s.cols-1].Wide {
+		l[s.cols-1] = s.erasedCell()
+	}
+	s.wrapPending = false
+}
+
+func (s *Screen) deleteChars(n int) {
+	l := s.lines[s.y]
+	n = clamp(n, 0, s.cols-s.x)
+	s.splitWide(s.x, s.y)
+	if s.x+n < s.cols {
+		s.splitWide(s.x+n, s.y)
+	}
+	copy(l[s.x:], l[s.x+n:])
+	for x := s.cols - n; x < s.cols; x++ {
+		l[x] = s.erasedCell()
+	}
+	s.wrapPending = false
+}
+
+func (s *Screen) erase(y, from, to int) {
+	if from < to {
+		s.clearCell(from, y)
+		s.clearCell(to-1, y)
+	}
+	for x := from; x < to; x++ {
+		s.lines[y][x] = s.erasedCell()
+	}
+}
+
+func (s *Screen) eraseLine(mode int) {
+	switch mode {
+	case 0:
+		s.erase(s.y, s.x, s.cols)
+	case 1:
+		s.erase(s.y, 0, s.x+1)
+	case 2:
+		s.erase(s.y, 0, s.cols)
+	}
+	s.wrapPending = false
+}
+
+func (s *Screen) eraseDisplay(mode int) {
+	switch mode {
+	case 0:
+		s.eraseLine(0)
+		for y := s.y + 1; y < s.rows; y++ {
+			s.erase(y, 0, s.cols)
+		}
+	case 1:
+		s.eraseLine(1)
+		for y := 0; y < s.y; y++ {
+			s.erase(y, 0, s.cols)
+		}
+	case 2:
+		for y := 0; y < s.rows; y++ {
+			s.erase(y, 0, s.cols)
+		}
+	case 3:
+		s.scrollback = nil
+	}
+}
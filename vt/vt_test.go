@@ -0,0 +1,173 @@
+package vt
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tatsushid/termdeco"
+)
+
+func TestScreenDecoration(t *testing.T) {
+	s := New(10, 3)
+	termdeco.Fprint(s, "a", termdeco.Red("b").BgGreen().Bold(), "c")
+
+	expected := []Cell{
+		{Text: "a"},
+		{Text: "b", Style: Style{Fg: IndexedColor(1), Bg: IndexedColor(2), Attrs: Bold}},
+		{Text: "c"},
+	}
+	for x, c := range expected {
+		if got := s.Cell(x, 0); got != c {
+			t.Errorf("Cell(%d, 0) = %+v, expected %+v", x, got, c)
+		}
+	}
+	if x, y, _ := s.Cursor(); x != 3 || y != 0 {
+		t.Errorf("Cursor = (%d, %d), expected (3, 0)", x, y)
+	}
+}
+
+func TestScreenSplitWrite(t *testing.T) {
+	s := New(10, 3)
+	s.Write([]byte("\x1b[38;5;"))
+	s.Write([]byte("200mx\xe3\x81"))
+	s.Write([]byte("\x82"))
+
+	if c := s.Cell(0, 0); c.Text != "x" || c.Style.Fg != IndexedColor(200) {
+		t.Errorf("Cell(0, 0) = %+v", c)
+	}
+	if c := s.Cell(1, 0); c.Text != "あ" || !c.Wide {
+		t.Errorf("Cell(1, 0) = %+v", c)
+	}
+	if x, _, _ := s.Cursor(); x != 3 {
+		t.Errorf("cursor x = %d, expected 3", x)
+	}
+}
+
+func TestScreenWrapAndScroll(t *testing.T) {
+	s := New(3, 2)
+	s.Write([]byte("abcdef\r\nghi"))
+
+	if s.String() != "def\nghi" {
+		t.Errorf("String = %q, expected %q", s.String(), "def\nghi")
+	}
+	if sb := s.Scrollback(); !reflect.DeepEqual(sb, []string{"abc"}) {
+		t.Errorf("Scrollback = %q, expected [abc]", sb)
+	}
+
+	s.Write([]byte("\x1b[1;2H\x1b[K"))
+	if s.Line(0) != "d" {
+		t.Errorf("Line(0) = %q, expected %q", s.Line(0), "d")
+	}
+}
+
+func TestScreenWideAndCombining(t *testing.T) {
+	s := New(5, 2)
+	s.Write([]byte("é日本語"))
+	if s.String() != "é日本\n語" {
+		t.Errorf("String = %q", s.String())
+	}
+	s.Write([]byte("\x1b[1;3Hx"))
+	if s.Line(0) != "é x本" {
+		t.Errorf("Line(0) = %q after overwriting a half of a wide character", s.Line(0))
+	}
+
+	s = New(6, 1)
+	s.Write([]byte("👨‍👩‍👧🇯🇵!"))
+	if c := s.Cell(0, 0); c.Text != "👨‍👩‍👧" || !c.Wide {
+		t.Errorf("Cell(0, 0) = %+v, expected a wide emoji ZWJ sequence", c)
+	}
+	if x, _, _ := s.Cursor(); x != 5 || s.Cell(2, 0).Text != "🇯🇵" {
+		t.Errorf("Line(0) = %q, cursor %d, expected a flag in a wide cell", s.Line(0), x)
+	}
+}
+
+func TestScreenScrollRegion(t *testing.T) {
+	s := New(4, 4)
+	s.Write([]byte("1\r\n2\r\n3\r\n4\x1b[2;3r\x1b[3;1Hx\ny\r\nz"))
+	if s.String() != "1\n y\nz\n4" {
+		t.Errorf("String = %q", s.String())
+	}
+	if len(s.Scrollback()) != 0 {
+		t.Errorf("Scrollback = %q, expected nothing", s.Scrollback())
+	}
+
+	s.Write([]byte("\x1b[r\x1b[2;1H\x1b[L"))
+	if s.String() != "1\n\n y\nz" {
+		t.Errorf("String = %q after IL", s.String())
+	}
+	s.Write([]byte("\x1b[M\x1b[M"))
+	if s.String() != "1\nz" {
+		t.Errorf("String = %q after DL", s.String())
+	}
+}
+
+func TestScreenInsertDeleteChars(t *testing.T) {
+	s := New(6, 1)
+	s.Write([]byte("abcdef\x1b[1;2H\x1b[2@"))
+	if s.Line(0) != "a  bcd" {
+		t.Errorf("Line = %q after ICH", s.Line(0))
+	}
+	s.Write([]byte("\x1b[3P"))
+	if s.Line(0) != "acd" {
+		t.Errorf("Line = %q after DCH", s.Line(0))
+	}
+	s.Write([]byte("\x1b[41m\x1b[X"))
+	if c := s.Cell(1, 0); c.Text != " " || c.Style.Bg != IndexedColor(1) {
+		t.Errorf("Cell(1, 0) = %+v after ECH", c)
+	}
+}
+
+func TestScreenAltScreen(t *testing.T) {
+	s := New(5, 2)
+	s.Write([]byte("main\x1b[?1049h\x1b[Halt\x1b[?25l"))
+	if !s.AltScreen() || s.String() != "alt" {
+		t.Errorf("AltScreen = %v, String = %q", s.AltScreen(), s.String())
+	}
+	if _, _, visible := s.Cursor(); visible {
+		t.Error("cursor should be hidden")
+	}
+	s.Write([]byte("\x1b[?1049l"))
+	if s.AltScreen() || s.String() != "main" {
+		t.Errorf("AltScreen = %v, String = %q", s.AltScreen(), s.String())
+	}
+	if x, y, _ := s.Cursor(); x != 4 || y != 0 {
+		t.Errorf("Cursor = (%d, %d), expected restored (4, 0)", x, y)
+	}
+}
+
+func TestScreenSaveRestoreAndTitle(t *testing.T) {
+	s := New(10, 3)
+	s.Write([]byte("\x1b[1m\x1b7\x1b[0m\x1b[3;5Hx\x1b8y\x1b]2;hello\x07"))
+	if c := s.Cell(0, 0); c.Text != "y" || c.Style.Attrs != Bold {
+		t.Errorf("Cell(0, 0) = %+v", c)
+	}
+	if s.Title() != "hello" {
+		t.Errorf("Title = %q", s.Title())
+	}
+	s.Write([]byte("\x1bc"))
+	if s.String() != "" || s.Title() != "" {
+		t.Errorf("String = %q, Title = %q after RIS", s.String(), s.Title())
+	}
+}
+
+func TestScreenCursorHelpers(t *testing.T) {
+	s := New(5, 3)
+	termdeco.Fprint(s, "abc", termdeco.CursorPosition(2, 3), "x",
+		termdeco.CursorUp(1), termdeco.CursorBack(2), termdeco.EraseLine(termdeco.EraseToEnd),
+		termdeco.CursorDown(2), termdeco.CursorColumn(1), "z", termdeco.HideCursor)
+	if s.String() != "a\n  x\nz" {
+		t.Errorf("String = %q", s.String())
+	}
+	if _, _, visible := s.Cursor(); visible {
+		t.Error("cursor should be hidden")
+	}
+
+	termdeco.Fprint(s, termdeco.SaveCursor, termdeco.CursorPosition(1, 5), "q", termdeco.RestoreCursor, "w")
+	if s.String() != "a   q\n  x\nzw" {
+		t.Errorf("String = %q after restore", s.String())
+	}
+	termdeco.Fprint(s, termdeco.EraseDisplay(termdeco.EraseAll), termdeco.ShowCursor)
+	if _, _, visible := s.Cursor(); s.String() != "" || !visible {
+		t.Errorf("String = %q, visible = %v after erase", s.String(), visible)
+	}
+}
@@ -0,0 +1,295 @@
+package termdeco
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// AmbiguousWidth is the number of columns which East Asian Ambiguous
+// characters like '○' and 'α' occupy. It is 1 for most terminals and should
+// be set to 2 for terminals configured for CJK environments.
+var AmbiguousWidth = 1
+
+type runeRange struct {
+	lo, hi rune
+}
+
+// wideRanges is ranges of East Asian Wide and Fullwidth characters and
+// emoji presented as wide characters by default.
+var wideRanges = []runeRange{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x2329, 0x232a}, {0x23e9, 0x23ec},
+	{0x23f0, 0x23f0}, {0x23f3, 0x23f3}, {0x25fd, 0x25fe}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267f, 0x267f}, {0x2693, 0x2693}, {0x26a1, 0x26a1},
+	{0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5}, {0x26ce, 0x26ce},
+	{0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5},
+	{0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b},
+	{0x2728, 0x2728}, {0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27b0, 0x27b0}, {0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55}, {0x2e80, 0x303e},
+	{0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf},
+	{0xa960, 0xa97f}, {0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe10, 0xfe19},
+	{0xfe30, 0xfe6f}, {0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x16fe0, 0x16fe4},
+	{0x17000, 0x18cff}, {0x1b000, 0x1b2ff}, {0x1f004, 0x1f004}, {0x1f0cf, 0x1f0cf},
+	{0x1f18e, 0x1f18e}, {0x1f191, 0x1f19a}, {0x1f200, 0x1f251}, {0x1f300, 0x1f320},
+	{0x1f32d, 0x1f335}, {0x1f337, 0x1f37c}, {0x1f37e, 0x1f393}, {0x1f3a0, 0x1f3ca},
+	{0x1f3cf, 0x1f3d3}, {0x1f3e0, 0x1f3f0}, {0x1f3f4, 0x1f3f4}, {0x1f3f8, 0x1f43e},
+	{0x1f440, 0x1f440}, {0x1f442, 0x1f4fc}, {0x1f4ff, 0x1f53d}, {0x1f54b, 0x1f54e},
+	{0x1f550, 0x1f567}, {0x1f57a, 0x1f57a}, {0x1f595, 0x1f596}, {0x1f5a4, 0x1f5a4},
+	{0x1f5fb, 0x1f64f}, {0x1f680, 0x1f6c5}, {0x1f6cc, 0x1f6cc}, {0x1f6d0, 0x1f6d2},
+	{0x1f6d5, 0x1f6d7}, {0x1f6dc, 0x1f6df}, {0x1f6eb, 0x1f6ec}, {0x1f6f4, 0x1f6fc},
+	{0x1f7e0, 0x1f7eb}, {0x1f7f0, 0x1f7f0}, {0x1f90c, 0x1f93a}, {0x1f93c, 0x1f945},
+	{0x1f947, 0x1f9ff}, {0x1fa70, 0x1faff}, {0x20000, 0x2fffd}, {0x30000, 0x3fffd},
+}
+
+// ambiguousRanges is ranges of East Asian Ambiguous characters.
+var ambiguousRanges = []runeRange{
+	{0x00a1, 0x00a1}, {0x00a4, 0x00a4}, {0x00a7, 0x00a8}, {0x00aa, 0x00aa},
+	{0x00ae, 0x00ae}, {0x00b0, 0x00b4}, {0x00b6, 0x00ba}, {0x00bc, 0x00bf},
+	{0x00c6, 0x00c6}, {0x00d0, 0x00d0}, {0x00d7, 0x00d8}, {0x00de, 0x00e1},
+	{0x00e6, 0x00e6}, {0x00e8, 0x00ea}, {0x00ec, 0x00ed}, {0x00f0, 0x00f0},
+	{0x00f2, 0x00f3}, {0x00f7, 0x00fa}, {0x00fc, 0x00fc}, {0x00fe, 0x00fe},
+	{0x0101, 0x0101}, {0x0111, 0x0111}, {0x0113, 0x0113}, {0x011b, 0x011b},
+	{0x0126, 0x0127}, {0x012b, 0x012b}, {0x0131, 0x0133}, {0x0138, 0x0138},
+	{0x013f, 0x0142}, {0x0144, 0x0144}, {0x0148, 0x014b}, {0x014d, 0x014d},
+	{0x0152, 0x0153}, {0x0166, 0x0167}, {0x016b, 0x016b}, {0x01ce, 0x01ce},
+	{0x01d0, 0x01d0}, {0x01d2, 0x01d2}, {0x01d4, 0x01d4}, {0x01d6, 0x01d6},
+	{0x01d8, 0x01d8}, {0x01da, 0x01da}, {0x01dc, 0x01dc}, {0x0251, 0x0251},
+	{0x0261, 0x0261}, {0x02c4, 0x02c4}, {0x02c7, 0x02c7}, {0x02c9, 0x02cb},
+	{0x02cd, 0x02cd}, {0x02d0, 0x02d0}, {0x02d8, 0x02db}, {0x02dd, 0x02dd},
+	{0x02df, 0x02df}, {0x0391, 0x03a1}, {0x03a3, 0x03a9}, {0x03b1, 0x03c1},
+	{0x03c3, 0x03c9}, {0x0401, 0x0401}, {0x0410, 0x044f}, {0x0451, 0x0451},
+	{0x2010, 0x2010}, {0x2013, 0x2016}, {0x2018, 0x2019}, {0x201c, 0x201d},
+	{0x2020, 0x2022}, {0x2024, 0x2027}, {0x2030, 0x2030}, {0x2032, 0x2033},
+	{0x2035, 0x2035}, {0x203b, 0x203b}, {0x203e, 0x203e}, {0x2074, 0x2074},
+	{0x207f, 0x207f}, {0x2081, 0x2084}, {0x20ac, 0x20ac}, {0x2103, 0x2103},
+	{0x2105, 0x2105}, {0x2109, 0x2109}, {0x2113, 0x2113}, {0x2116, 0x2116},
+	{0x2121, 0x2122}, {0x2126, 0x2126}, {0x212b, 0x212b}, {0x2153, 0x2154},
+	{0x215b, 0x215e}, {0x2160, 0x216b}, {0x2170, 0x2179}, {0x2189, 0x2189},
+	{0x2190, 0x2199}, {0x21b8, 0x21b9}, {0x21d2, 0x21d2}, {0x21d4, 0x21d4},
+	{0x21e7, 0x21e7}, {0x2200, 0x2200}, {0x2202, 0x2203}, {0x2207, 0x2208},
+	{0x220b, 0x220b}, {0x220f, 0x220f}, {0x2211, 0x2211}, {0x2215, 0x2215},
+	{0x221a, 0x221a}, {0x221d, 0x2220}, {0x2223, 0x2223}, {0x2225, 0x2225},
+	{0x2227, 0x222c}, {0x222e, 0x222e}, {0x2234, 0x2237}, {0x223c, 0x223d},
+	{0x2248, 0x2248}, {0x224c, 0x224c}, {0x2252, 0x2252}, {0x2260, 0x2261},
+	{0x2264, 0x2267}, {0x226a, 0x226b}, {0x226e, 0x226f}, {0x2282, 0x2283},
+	{0x2286, 0x2287}, {0x2295, 0x2295}, {0x2299, 0x2299}, {0x22a5, 0x22a5},
+	{0x22bf, 0x22bf}, {0x2312, 0x2312}, {0x2460, 0x24e9}, {0x24eb, 0x254b},
+	{0x2550, 0x2573}, {0x2580, 0x258f}, {0x2592, 0x2595}, {0x25a0, 0x25a1},
+	{0x25a3, 0x25a9}, {0x25b2, 0x25b3}, {0x25b6, 0x25b7}, {0x25bc, 0x25bd},
+	{0x25c0, 0x25c1}, {0x25c6, 0x25c8}, {0x25cb, 0x25cb}, {0x25ce, 0x25d1},
+	{0x25e2, 0x25e5}, {0x25ef, 0x25ef}, {0x2605, 0x2606}, {0x2609, 0x2609},
+	{0x260e, 0x260f}, {0x261c, 0x261c}, {0x261e, 0x261e}, {0x2640, 0x2640},
+	{0x2642, 0x2642}, {0x2660, 0x2661}, {0x2663, 0x2665}, {0x2667, 0x266a},
+	{0x266c, 0x266d}, {0x266f, 0x266f}, {0x269e, 0x269f}, {0x26bf, 0x26bf},
+	{0x26c6, 0x26cd}, {0x26cf, 0x26d3}, {0x26d5, 0x26e1}, {0x26e3, 0x26e3},
+	{0x26e8, 0x26e9}, {0x26eb, 0x26f1}, {0x26f4, 0x26f4}, {0x26f6, 0x26f9},
+	{0x26fb, 0x26fc}, {0x26fe, 0x26ff}, {0x273d, 0x273d}, {0x2776, 0x277f},
+	{0x2b56, 0x2b59}, {0x3248, 0x324f}, {0xe000, 0xf8ff}, {0xfffd, 0xfffd},
+	{0x1f100, 0x1f10a}, {0x1f110, 0x1f12d}, {0x1f130, 0x1f169}, {0x1f170, 0x1f18d},
+	{0x1f18f, 0x1f190}, {0x1f19b, 0x1f1ac}, {0xf0000, 0xffffd}, {0x100000, 0x10fffd},
+}
+
+// pictographicRanges is ranges of Extended_Pictographic characters which
+// form emoji ZWJ sequences.
+var pictographicRanges = []runeRange{
+	{0x00a9, 0x00a9}, {0x00ae, 0x00ae}, {0x203c, 0x203c}, {0x2049, 0x2049},
+	{0x2122, 0x2122}, {0x2139, 0x2139}, {0x2194, 0x2199}, {0x21a9, 0x21aa},
+	{0x231a, 0x231b}, {0x2328, 0x2328}, {0x2388, 0x2388}, {0x23cf, 0x23cf},
+	{0x23e9, 0x23f3}, {0x23f8, 0x23fa}, {0x24c2, 0x24c2}, {0x25aa, 0x25ab},
+	{0x25b6, 0x25b6}, {0x25c0, 0x25c0}, {0x25fb, 0x25fe}, {0x2600, 0x2605},
+	{0x2607, 0x2612}, {0x2614, 0x2685}, {0x2690, 0x2705}, {0x2708, 0x2712},
+	{0x2714, 0x2714}, {0x2716, 0x2716}, {0x271d, 0x271d}, {0x2721, 0x2721},
+	{0x2728, 0x2728}, {0x2733, 0x2734}, {0x2744, 0x2744}, {0x2747, 0x2747},
+	{0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755}, {0x2757, 0x2757},
+	{0x2763, 0x2767}, {0x2795, 0x2797}, {0x27a1, 0x27a1}, {0x27b0, 0x27b0},
+	{0x27bf, 0x27bf}, {0x2934, 0x2935}, {0x2b05, 0x2b07}, {0x2b1b, 0x2b1c},
+	{0x2b50, 0x2b50}, {0x2b55, 0x2b55}, {0x3030, 0x3030}, {0x303d, 0x303d},
+	{0x3297, 0x3297}, {0x3299, 0x3299}, {0x1f000, 0x1f0ff}, {0x1f10d, 0x1f10f},
+	{0x1f12f, 0x1f12f}, {0x1f16c, 0x1f171}, {0x1f17e, 0x1f17f}, {0x1f18e, 0x1f18e},
+	{0x1f191, 0x1f19a}, {0x1f1ad, 0x1f1e5}, {0x1f201, 0x1f20f}, {0x1f21a, 0x1f21a},
+	{0x1f22f, 0x1f22f}, {0x1f232, 0x1f23a}, {0x1f23c, 0x1f23f}, {0x1f249, 0x1f3fa},
+	{0x1f400, 0x1f53d}, {0x1f546, 0x1f64f}, {0x1f680, 0x1f6ff}, {0x1f774, 0x1f77f},
+	{0x1f7d5, 0x1f7ff}, {0x1f80c, 0x1f80f}, {0x1f848, 0x1f84f}, {0x1f85a, 0x1f85f},
+	{0x1f888, 0x1f88f}, {0x1f8ae, 0x1f8ff}, {0x1f90c, 0x1f93a}, {0x1f93c, 0x1f945},
+	{0x1f947, 0x1faff}, {0x1fc00, 0x1fffd},
+}
+
+func inRanges(r rune, ranges []runeRange) bool {
+	lo, hi := 0, len(ranges)
+	for lo < hi {
+		m := (lo + hi) / 2
+		switch {
+		case r < ranges[m].lo:
+			hi = m
+		case r > ranges[m].hi:
+			lo = m + 1
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	zeroWidthJoiner   = 0x200d
+	textPresentation  = 0xfe0e
+	emojiPresentation = 0xfe0f
+	combiningKeycap   = 0x20e3
+)
+
+// isExtend reports whether r extends the preceding character into a grapheme
+// cluster, like combining marks, variation selectors and emoji modifiers.
+func isExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == zeroWidthJoiner || (r >= 0x1f3fb && r <= 0x1f3ff) || (r >= 0xe0020 && r <= 0xe007f)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// Hangul syllable types for grapheme cluster boundaries.
+const (
+	hangulNone = iota
+	hangulL
+	hangulV
+	hangulT
+	hangulLV
+	hangulLVT
+)
+
+func hangulType(r rune) int {
+	switch {
+	case r >= 0x1100 && r <= 0x115f, r >= 0xa960 && r <= 0xa97c:
+		return hangulL
+	case r >= 0x1160 && r <= 0x11a7, r >= 0xd7b0 && r <= 0xd7c6:
+		return hangulV
+	case r >= 0x11a8 && r <= 0x11ff, r >= 0xd7cb && r <= 0xd7fb:
+		return hangulT
+	case r >= 0xac00 && r <= 0xd7a3:
+		if (r-0xac00)%28 == 0 {
+			return hangulLV
+		}
+		return hangulLVT
+	}
+	return hangulNone
+}
+
+// hangulJoins reports whether Hangul syllable types a and b are in a
+// grapheme cluster.
+func hangulJoins(a, b int) bool {
+	switch a {
+	case hangulL:
+		return b == hangulL || b == hangulV || b == hangulLV || b == hangulLVT
+	case hangulV, hangulLV:
+		return b == hangulV || b == hangulT
+	case hangulT, hangulLVT:
+		return b == hangulT
+	}
+	return false
+}
+
+// RuneWidth returns the number of columns r occupies on a terminal. It is 0
+// for control characters, combining marks and other zero-width characters, 2
+// for East Asian Wide and Fullwidth characters and AmbiguousWidth for East
+// Asian Ambiguous characters.
+func RuneWidth(r rune) int {
+	switch {
+	case isControl(r):
+		return 0
+	case r < 0x300:
+		if r >= 0xa1 && inRanges(r, ambiguousRanges) {
+			return AmbiguousWidth
+		}
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf), r >= 0x1160 && r <= 0x11ff:
+		return 0
+	case inRanges(r, wideRanges):
+		return 2
+	case inRanges(r, ambiguousRanges):
+		return AmbiguousWidth
+	}
+	return 1
+}
+
+// NextGrapheme returns the first grapheme cluster of s, a user-perceived
+// character like "e" with a combining accent, an emoji ZWJ sequence or a flag,
+// and the number of columns it occupies. s must not start with an escape
+// sequence.
+func NextGrapheme(s string) (g string, width int) {
+	if s == "" {
+		return "", 0
+	}
+	first, n := utf8.DecodeRuneInString(s)
+	if first == '\r' && len(s) > 1 && s[1] == '\n' {
+		return s[:2], 0
+	}
+	if isControl(first) {
+		return s[:n], 0
+	}
+
+	prev := first
+	hangul := hangulType(first)
+	pictographic := inRanges(first, pictographicRanges)
+	regional := 0
+	if isRegionalIndicator(first) {
+		regional = 1
+	}
+	presentation := rune(0)
+	keycap := false
+	i := n
+	for i < len(s) {
+		r, n := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case isExtend(r):
+			if r == textPresentation || r == emojiPresentation {
+				presentation = r
+			}
+			if r == combiningKeycap {
+				keycap = true
+			}
+		case prev == zeroWidthJoiner && pictographic && inRanges(r, pictographicRanges):
+		case regional == 1 && isRegionalIndicator(r):
+			regional++
+		case hangul != hangulNone && hangulJoins(hangul, hangulType(r)):
+			hangul = hangulType(r)
+		default:
+			return s[:i], graphemeWidth(first, regional, presentation, keycap)
+		}
+		prev = r
+		i += n
+	}
+	return s, graphemeWidth(first, regional, presentation, keycap)
+}
+
+func graphemeWidth(base rune, regional int, presentation rune, keycap bool) int {
+	switch {
+	case regional == 2:
+		return 2
+	case presentation == textPresentation && inRanges(base, pictographicRanges):
+		return 1
+	case presentation == emojiPresentation && (inRanges(base, pictographicRanges) || keycap):
+		return 2
+	}
+	return RuneWidth(base)
+}
+
+// Width returns the number of columns s occupies on a terminal. Escape
+// sequences and control characters are ignored and each grapheme cluster is
+// measured as a whole.
+func Width(s string) int {
+	w := 0
+	for s != "" {
+		t := NextToken(s)
+		s = s[len(t.Raw):]
+		if t.Kind != TextToken {
+			continue
+		}
+		for text := t.Raw; text != ""; {
+			g, gw := NextGrapheme(text)
+			w += gw
+			text = text[len(g):]
+		}
+	}
+	return w
+}
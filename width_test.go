@@ -0,0 +1,83 @@
+package termdeco
+
+import "testing"
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"日本語", 6},
+		{"ｱｲｳ", 3},
+		{"\x1b[31mエラー\x1b[0m: x", 9},
+		{"é", 1},
+		{"́", 0},
+		{"a​b", 2},
+		{"한국", 4},
+		{"각", 2},
+		{"👍", 2},
+		{"👍🏽", 2},
+		{"👨‍👩‍👧", 2},
+		{"🇯🇵", 2},
+		{"❤", 1},
+		{"❤️", 2},
+		{"⌚︎", 1},
+		{"1️⃣", 2},
+		{"a\tb\r\n", 2},
+	}
+	for _, tt := range tests {
+		if w := Width(tt.s); w != tt.expected {
+			t.Errorf("Width(%q) = %d, expected %d", tt.s, w, tt.expected)
+		}
+	}
+}
+
+func TestWidthAmbiguous(t *testing.T) {
+	defer func(w int) { AmbiguousWidth = w }(AmbiguousWidth)
+
+	s := "○α─"
+	if w := Width(s); w != 3 {
+		t.Errorf("Width(%q) = %d, expected 3", s, w)
+	}
+	AmbiguousWidth = 2
+	if w := Width(s); w != 6 {
+		t.Errorf("Width(%q) = %d with AmbiguousWidth 2, expected 6", s, w)
+	}
+}
+
+func TestNextGrapheme(t *testing.T) {
+	s := "é👨‍👩🇯🇵🇺🇸x"
+	expected := []string{"é", "👨‍👩", "🇯🇵", "🇺🇸", "x"}
+	var got []string
+	for s != "" {
+		g, _ := NextGrapheme(s)
+		got = append(got, g)
+		s = s[len(g):]
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("NextGrapheme split into %q, expected %q", got, expected)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("grapheme %d = %q, expected %q", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestDecoratorWidth(t *testing.T) {
+	tests := []struct {
+		got, expected string
+	}{
+		{Sprintf("%-6v|", Red("日本").Trusted()), "\x1b[31m日本  \x1b[0m|"},
+		{Sprintf("%5s|", Green("é").Trusted()), "\x1b[32m    é\x1b[0m|"},
+		{Sprintf("%05d|", Blue(42)), "\x1b[34m00042\x1b[0m|"},
+		{Sprintf("%4v|", Bold("\x07")), "\x1b[1m\\x07\x1b[0m|"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("got %q, expected %q", tt.got, tt.expected)
+		}
+	}
+}
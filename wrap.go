@@ -0,0 +1,220 @@
+package termdeco
+
+import (
+	"strings"
+)
+
+// WrapOptions is options of Wrap.
+type WrapOptions struct {
+	// Prefix is put at the start of every line like "> " for quoting.
+	Prefix string
+	// Indent is put after Prefix at the start of continuation lines of each
+	// paragraph, which makes a hanging indent.
+	Indent string
+	// BreakWords breaks words wider than the width. Otherwise they are put
+	// on their own lines and overflow.
+	BreakWords bool
+	// Justify widens spaces between words so every line except the last
+	// one of each paragraph fills the width.
+	Justify bool
+}
+
+// wrapWord is a word and spaces before it. text may have escape sequences.
+type wrapWord struct {
+	sep   string
+	text  string
+	width int
+}
+
+// Wrap wraps s at word boundaries so each line occupies at most width
+// columns, counted by Width including the prefix and indent in opts. Existing
+// line breaks separate paragraphs. Styles in effect at a line end are reset
+// and started again on the next line, so prefixes and indents are never
+// styled. opts may be nil.
+func Wrap(s string, width int, opts *WrapOptions) string {
+	if opts == nil {
+		opts = &WrapOptions{}
+	}
+	var out []string
+	for _, para := range Split(s) {
+		lines, limits := wrapParagraph(splitWords(para), width, opts)
+		for i, l := range Split(strings.Join(justifyLines(lines, limits, opts), "\n")) {
+			if i == 0 {
+				out = append(out, opts.Prefix+l)
+			} else {
+				out = append(out, opts.Prefix+opts.Indent+l)
+			}
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// splitWords splits a single line s into words separated by spaces. Escape
+// sequences are kept in the word following them except trailing ones which
+// are kept in the last word.
+func splitWords(s string) []wrapWord {
+	var words []wrapWord
+	var cur wrapWord
+	visible := false
+	for s != "" {
+		t := NextToken(s)
+		s = s[len(t.Raw):]
+		if t.Kind != TextToken {
+			cur.text += t.Raw
+			continue
+		}
+		for text := t.Raw; text != ""; {
+			g, w := NextGrapheme(text)
+			text = text[len(g):]
+			if g == " " {
+				if visible {
+					words = append(words, cur)
+					cur, visible = wrapWord{}, false
+				}
+				cur.sep += g
+				continue
+			}
+			cur.text += g
+			cur.width += w
+			visible = true
+		}
+	}
+	switch {
+	case visible:
+		words = append(words, cur)
+	case len(words) > 0:
+		words[len(words)-1].text += cur.text
+	case cur.text != "" || cur.sep != "":
+		words = append(words, cur)
+	}
+	return words
+}
+
+// cutWord cuts s after width columns. At least one character is kept in head
+// even if it is wider than width.
+func cutWord(s string, width int) (head, rest string) {
+	col := 0
+	for i := 0; i < len(s); {
+		t := NextToken(s[i:])
+		if t.Kind != TextToken {
+			i += len(t.Raw)
+			continue
+		}
+		for j := 0; j < len(t.Raw); {
+			g, w := NextGrapheme(t.Raw[j:])
+			if col+w > width && col > 0 {
+				return s[:i+j], s[i+j:]
+			}
+			col += w
+			j += len(g)
+		}
+		i += len(t.Raw)
+	}
+	return s, ""
+}
+
+// wrapParagraph fills words into lines and returns them with the number of
+// columns available for each line.
+func wrapParagraph(words []wrapWord, width int, opts *WrapOptions) ([][]wrapWord, []int) {
+	first := width - Width(opts.Prefix)
+	rest := first - Width(opts.Indent)
+	var lines [][]wrapWord
+	var limits []int
+	var cur []wrapWord
+	curWidth := 0
+	limit := first
+	flush := func() {
+		lines = append(lines, cur)
+		limits = append(limits, limit)
+		cur, curWidth, limit = nil, 0, rest
+	}
+	for i := 0; i < len(words); {
+		w := words[i]
+		if len(cur) == 0 && len(lines) > 0 {
+			w.sep = ""
+		}
+		need := curWidth + len(w.sep) + w.width
+		if len(cur) == 0 && need > limit {
+			// leading spaces don't push a word fitting the limit over
+			// it nor leave no room to break a longer one
+			switch {
+			case w.width <= limit:
+				w.sep = w.sep[:limit-w.width]
+			case opts.BreakWords && len(w.sep) >= limit && limit > 0:
+				w.sep = w.sep[:limit-1]
+			}
+			need = len(w.sep) + w.width
+		}
+		switch {
+		case need <= limit || (len(cur) == 0 && !opts.BreakWords):
+			cur = append(cur, w)
+			curWidth = need
+			i++
+		case opts.BreakWords && (len(cur) == 0 || w.width > rest):
+			room := limit - curWidth - len(w.sep)
+			if len(cur) > 0 && room <= 0 {
+				flush()
+				continue
+			}
+			head, tail := cutWord(w.text, room)
+			hw := Width(head)
+			cur = append(cur, wrapWord{sep: w.sep, text: head, width: hw})
+			curWidth += len(w.sep) + hw
+			words[i] = wrapWord{text: tail, width: w.width - hw}
+			if tail == "" || Width(tail) == 0 {
+				// keep trailing escape sequences on this line
+				cur[len(cur)-1].text += tail
+				i++
+				continue
+			}
+			flush()
+		default:
+			flush()
+		}
+	}
+	if len(cur) > 0 || len(lines) == 0 {
+		flush()
+	}
+	return lines, limits
+}
+
+// justifyLines joins words of each line. With Justify, spaces between words
+// are widened to fill the limit except the last line.
+func justifyLines(lines [][]wrapWord, limits []int, opts *WrapOptions) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		var b strings.Builder
+		if opts.Justify && i < len(lines)-1 && len(l) > 1 {
+			used := len(l[0].sep)
+			for j, w := range l {
+				used += w.width
+				if j > 0 {
+					used++
+				}
+			}
+			gaps := len(l) - 1
+			extra := limits[i] - used
+			if extra < 0 {
+				extra = 0
+			}
+			for j, w := range l {
+				switch {
+				case j == 0:
+					b.WriteString(w.sep)
+				case j <= extra%gaps:
+					b.WriteString(strings.Repeat(" ", 2+extra/gaps))
+				default:
+					b.WriteString(strings.Repeat(" ", 1+extra/gaps))
+				}
+				b.WriteString(w.text)
+			}
+		} else {
+			for _, w := range l {
+				b.WriteString(w.sep)
+				b.WriteString(w.text)
+			}
+		}
+		out[i] = b.String()
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package termdeco
+
+import (
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		s        string
+		width    int
+		opts     *WrapOptions
+		expected string
+	}{
+		{"the quick brown fox jumps", 10, nil, "the quick\nbrown fox\njumps"},
+		{"  indented text here", 10, nil, "  indented\ntext here"},
+		{"a \x1b[31mred word\x1b[0m ok", 7, nil, "a \x1b[31mred\x1b[0m\n\x1b[31mword\x1b[0m ok"},
+		{"first para\n\nsecond", 8, nil, "first\npara\n\nsecond"},
+		{"-v  verbose output for debugging", 16, &WrapOptions{Indent: "    "}, "-v  verbose\n    output for\n    debugging"},
+		{"quoted text wraps", 9, &WrapOptions{Prefix: "> "}, "> quoted\n> text\n> wraps"},
+		{"see https://example.com/long/path", 10, nil, "see\nhttps://example.com/long/path"},
+		{"see https://example.com/long/path", 10, &WrapOptions{BreakWords: true}, "see https:\n//example.\ncom/long/p\nath"},
+		{"日本語のテキスト", 6, &WrapOptions{BreakWords: true}, "日本語\nのテキ\nスト"},
+		{"a bb ccc dd e", 8, &WrapOptions{Justify: true}, "a bb ccc\ndd e"},
+		{"aa b cc dd", 9, &WrapOptions{Justify: true}, "aa  b  cc\ndd"},
+	}
+	for _, tt := range tests {
+		if got := Wrap(tt.s, tt.width, tt.opts); got != tt.expected {
+			t.Errorf("Wrap(%q, %d) = %q, expected %q", tt.s, tt.width, got, tt.expected)
+		}
+	}
+}
+
+func TestWrapLeadingSpaces(t *testing.T) {
+	tests := []struct {
+		s        string
+		opts     *WrapOptions
+		expected string
+	}{
+		{"  👍", nil, " 👍"},
+		{"    ab cd", nil, " ab\ncd"},
+		{"    abcdef", &WrapOptions{BreakWords: true}, "  a\nbcd\nef"},
+	}
+	for _, tt := range tests {
+		got := Wrap(tt.s, 3, tt.opts)
+		if got != tt.expected {
+			t.Errorf("Wrap(%q, 3) = %q, expected %q", tt.s, got, tt.expected)
+		}
+		for _, l := range Split(got) {
+			if w := Width(l); w > 3 {
+				t.Errorf("Wrap(%q, 3) has a line %q of %d columns", tt.s, l, w)
+			}
+		}
+	}
+}